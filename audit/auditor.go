@@ -0,0 +1,175 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+	"github.com/husobee/vestigo"
+)
+
+const (
+	recordTimeout = 10 * time.Second
+
+	DropReasonQueueFull = "queue_full"
+	DropReasonSinkError = "sink_error"
+	DropReasonClosed    = "closed"
+	DropReasonShutdown  = "shutdown"
+)
+
+// DropObserver is notified of the audit events that could not be recorded to the Sink, with the reason.
+type DropObserver interface {
+	AuditEventDropped(reason string)
+}
+
+// Auditor keeps the recent audit events of the drafts in its Store, for support,
+// and records them to its Sink in the background, so that a slow sink does not delay draft requests.
+// Events that cannot be recorded, as the queue is full or the sink fails, are logged and reported to the DropObserver.
+// Reads are only audited when asked for. It implements content.DraftAuditor.
+type Auditor struct {
+	sink         Sink
+	store        *Store
+	includeReads bool
+	queue        chan content.AuditEvent
+	observer     DropObserver
+	log          *logger.UPPLogger
+
+	mu     sync.RWMutex
+	closed bool
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAuditor starts recording the events to the sink, which may be nil to only keep them in the store.
+// Up to queueSize events are queued for the sink. The observer may be nil.
+func NewAuditor(sink Sink, store *Store, includeReads bool, queueSize int, observer DropObserver, log *logger.UPPLogger) *Auditor {
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &Auditor{
+		sink:         sink,
+		store:        store,
+		includeReads: includeReads,
+		queue:        make(chan content.AuditEvent, queueSize),
+		observer:     observer,
+		log:          log,
+		ctx:          ctx,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+
+	go a.run()
+	return a
+}
+
+func (a *Auditor) Audit(_ context.Context, event content.AuditEvent) {
+	if !a.includeReads && (event.Action == content.AuditActionRead || event.Action == content.AuditActionReadNative) {
+		return
+	}
+	a.store.add(event)
+
+	if a.sink == nil {
+		return
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	eventLog := a.log.WithField(tidutils.TransactionIDHeader, event.TransactionID).WithField("uuid", event.UUID)
+	if a.closed {
+		eventLog.Error("Auditor is closed, dropping audit event")
+		a.dropped(DropReasonClosed)
+		return
+	}
+
+	select {
+	case a.queue <- event:
+	default:
+		eventLog.Error("Audit queue is full, dropping audit event")
+		a.dropped(DropReasonQueueFull)
+	}
+}
+
+// Close stops accepting events and records the queued ones until ctx is done, dropping those left,
+// then closes the sink if it is an io.Closer.
+func (a *Auditor) Close(ctx context.Context) {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		a.cancel()
+		<-a.done
+	}
+	a.cancel()
+
+	if closer, ok := a.sink.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+func (a *Auditor) run() {
+	defer close(a.done)
+
+	left := 0
+	for event := range a.queue {
+		if a.ctx.Err() != nil {
+			left++
+			a.dropped(DropReasonShutdown)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(a.ctx, recordTimeout)
+		if err := a.sink.Record(ctx, event); err != nil {
+			a.log.WithError(err).
+				WithField(tidutils.TransactionIDHeader, event.TransactionID).
+				WithField("uuid", event.UUID).
+				Error("Failed to record audit event")
+			a.dropped(DropReasonSinkError)
+		}
+		cancel()
+	}
+
+	if left > 0 {
+		a.log.WithField("events", left).Error("Audit events left unrecorded on shutdown")
+	}
+}
+
+func (a *Auditor) dropped(reason string) {
+	if a.observer != nil {
+		a.observer.AuditEventDropped(reason)
+	}
+}
+
+// ServeAudit returns the recent audit events of the draft of the uuid path parameter, newest first.
+func (a *Auditor) ServeAudit(w http.ResponseWriter, r *http.Request) {
+	contentUUID := strings.ToLower(vestigo.Param(r, "uuid"))
+	if _, err := uuid.Parse(contentUUID); err != nil {
+		writeMessage(w, fmt.Sprintf("Invalid content UUID: %v", contentUUID), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(a.store.Events(contentUUID))
+}
+
+func writeMessage(w http.ResponseWriter, errMsg string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	jsonMsg := fmt.Sprintf(`{"message": "%v"}`, errMsg)
+	w.Write([]byte(jsonMsg))
+}
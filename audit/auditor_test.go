@@ -0,0 +1,199 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/go-logger/v2"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	draftUUID = "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	otherUUID = "0e7ad51c-4a0b-11e7-8b62-0c6e6bd7e9a4"
+)
+
+type memorySink struct {
+	mu     sync.Mutex
+	events []content.AuditEvent
+}
+
+func (s *memorySink) Record(_ context.Context, event content.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+	return nil
+}
+
+type blockingSink struct {
+	recording chan struct{}
+}
+
+func (s *blockingSink) Record(ctx context.Context, _ content.AuditEvent) error {
+	s.recording <- struct{}{}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type dropCounter struct {
+	mu      sync.Mutex
+	reasons map[string]int
+}
+
+func (c *dropCounter) AuditEventDropped(reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reasons[reason]++
+}
+
+func writeEvent(contentUUID string, tid string) content.AuditEvent {
+	return content.AuditEvent{Action: content.AuditActionWrite, UUID: contentUUID, TransactionID: tid, Status: http.StatusOK, Outcome: content.AuditOutcomeSucceeded}
+}
+
+func TestStoreKeepsRecentEvents(t *testing.T) {
+	store := NewStore(2, 1)
+
+	store.add(writeEvent(draftUUID, "tid_1"))
+	store.add(writeEvent(draftUUID, "tid_2"))
+	store.add(writeEvent(draftUUID, "tid_3"))
+
+	events := store.Events(draftUUID)
+	require.Len(t, events, 2)
+	assert.Equal(t, "tid_3", events[0].TransactionID, "newest first")
+	assert.Equal(t, "tid_2", events[1].TransactionID)
+
+	store.add(writeEvent(otherUUID, "tid_4"))
+	assert.Empty(t, store.Events(draftUUID), "the least recently audited draft is evicted")
+	assert.Len(t, store.Events(otherUUID), 1)
+}
+
+func TestAuditorRecordsWrites(t *testing.T) {
+	sink := &memorySink{}
+	store := NewStore(10, 10)
+	auditor := NewAuditor(sink, store, false, 10, nil, logger.NewUPPLogger("test logger", "debug"))
+
+	auditor.Audit(context.TODO(), writeEvent(draftUUID, "tid_write"))
+	auditor.Audit(context.TODO(), content.AuditEvent{Action: content.AuditActionRead, UUID: draftUUID, TransactionID: "tid_read"})
+	auditor.Close(context.TODO())
+
+	require.Len(t, sink.events, 1)
+	assert.Equal(t, "tid_write", sink.events[0].TransactionID)
+	assert.Len(t, store.Events(draftUUID), 1, "reads are not audited unless asked for")
+}
+
+func TestAuditorRecordsReads(t *testing.T) {
+	store := NewStore(10, 10)
+	auditor := NewAuditor(nil, store, true, 10, nil, logger.NewUPPLogger("test logger", "debug"))
+	defer auditor.Close(context.TODO())
+
+	auditor.Audit(context.TODO(), content.AuditEvent{Action: content.AuditActionReadNative, UUID: draftUUID, TransactionID: "tid_read"})
+
+	assert.Len(t, store.Events(draftUUID), 1)
+}
+
+func TestAuditorCountsDroppedEvents(t *testing.T) {
+	sink := &blockingSink{recording: make(chan struct{}, 1)}
+	drops := &dropCounter{reasons: map[string]int{}}
+	auditor := NewAuditor(sink, NewStore(10, 10), false, 1, drops, logger.NewUPPLogger("test logger", "debug"))
+
+	auditor.Audit(context.TODO(), writeEvent(draftUUID, "tid_1"))
+	<-sink.recording
+	auditor.Audit(context.TODO(), writeEvent(draftUUID, "tid_2"))
+	auditor.Audit(context.TODO(), writeEvent(draftUUID, "tid_3"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	auditor.Close(ctx)
+	assert.Less(t, time.Since(start), time.Second, "Close does not wait past its deadline")
+
+	auditor.Audit(context.TODO(), writeEvent(draftUUID, "tid_4"))
+
+	assert.Equal(t, map[string]int{
+		DropReasonQueueFull: 1,
+		DropReasonSinkError: 1,
+		DropReasonShutdown:  1,
+		DropReasonClosed:    1,
+	}, drops.reasons)
+}
+
+func TestServeAudit(t *testing.T) {
+	auditor := NewAuditor(nil, NewStore(10, 10), false, 10, nil, logger.NewUPPLogger("test logger", "debug"))
+	defer auditor.Close(context.TODO())
+	auditor.Audit(context.TODO(), writeEvent(draftUUID, "tid_write"))
+
+	r := vestigo.NewRouter()
+	r.Get("/drafts/audit/:uuid", auditor.ServeAudit)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/drafts/audit/83A201C6-60CD-11E7-91A7-502F7EE26895", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var events []content.AuditEvent
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "tid_write", events[0].TransactionID)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/drafts/audit/"+otherUUID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/drafts/audit/not-a-uuid", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Record(context.TODO(), writeEvent(draftUUID, "tid_1")))
+	require.NoError(t, sink.Record(context.TODO(), writeEvent(draftUUID, "tid_2")))
+	require.NoError(t, sink.(io.Closer).Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var tids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event content.AuditEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		tids = append(tids, event.TransactionID)
+	}
+	assert.Equal(t, []string{"tid_1", "tid_2"}, tids)
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received content.AuditEvent
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, server.Client())
+	require.NoError(t, sink.Record(context.TODO(), writeEvent(draftUUID, "tid_1")))
+	assert.Equal(t, "tid_1", received.TransactionID)
+
+	status = http.StatusServiceUnavailable
+	assert.Error(t, sink.Record(context.TODO(), writeEvent(draftUUID, "tid_2")))
+}
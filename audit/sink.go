@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/Financial-Times/draft-content-api/content"
+)
+
+// Sink stores audit events durably, outside of the service.
+type Sink interface {
+	Record(ctx context.Context, event content.AuditEvent) error
+}
+
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink returns a Sink writing the events as JSON lines, e.g. to os.Stdout.
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) Record(_ context.Context, event content.AuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+type fileSink struct {
+	writerSink
+	file *os.File
+}
+
+// NewFileSink returns a Sink appending the events as JSON lines to the file, which is created if needed.
+// The sink must be closed.
+func NewFileSink(path string) (Sink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{writerSink: writerSink{w: file}, file: file}, nil
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+type httpSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewHTTPSink returns a Sink posting every event as JSON to the URL.
+func NewHTTPSink(url string, httpClient *http.Client) Sink {
+	return &httpSink{url: url, httpClient: httpClient}
+}
+
+func (s *httpSink) Record(ctx context.Context, event content.AuditEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("audit sink returned a non-2XX status code: %v", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/Financial-Times/draft-content-api/content"
+)
+
+// Store keeps the most recent audit events of the most recently audited drafts in memory.
+type Store struct {
+	maxEvents int
+	maxDrafts int
+
+	mu     sync.Mutex
+	drafts map[string]*list.Element
+	// lru holds the audited drafts from the most to the least recently audited
+	lru *list.List
+}
+
+type draftEvents struct {
+	uuid   string
+	events []content.AuditEvent
+}
+
+// NewStore returns a Store of up to maxEvents events for each of maxDrafts drafts.
+func NewStore(maxEvents int, maxDrafts int) *Store {
+	return &Store{
+		maxEvents: maxEvents,
+		maxDrafts: maxDrafts,
+		drafts:    map[string]*list.Element{},
+		lru:       list.New(),
+	}
+}
+
+func (s *Store) add(event content.AuditEvent) {
+	if s.maxEvents <= 0 || s.maxDrafts <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, found := s.drafts[event.UUID]
+	if found {
+		s.lru.MoveToFront(e)
+	} else {
+		e = s.lru.PushFront(&draftEvents{uuid: event.UUID})
+		s.drafts[event.UUID] = e
+	}
+
+	draft := e.Value.(*draftEvents)
+	draft.events = append(draft.events, event)
+	if len(draft.events) > s.maxEvents {
+		draft.events = draft.events[len(draft.events)-s.maxEvents:]
+	}
+
+	for s.lru.Len() > s.maxDrafts {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.drafts, oldest.Value.(*draftEvents).uuid)
+	}
+}
+
+// Events returns the recorded events of the draft, newest first.
+func (s *Store) Events(contentUUID string) []content.AuditEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := []content.AuditEvent{}
+	e, found := s.drafts[contentUUID]
+	if !found {
+		return result
+	}
+
+	events := e.Value.(*draftEvents).events
+	for i := len(events) - 1; i >= 0; i-- {
+		result = append(result, events[i])
+	}
+	return result
+}
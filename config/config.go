@@ -9,13 +9,26 @@ import (
 type Config struct {
 	ContentTypes map[string]ValidatorConfig   `yaml:"content-types"`
 	HealthChecks map[string]HealthCheckConfig `yaml:"end-point-health-checks"`
+	Webhooks     []WebhookConfig              `yaml:"webhooks"`
+	WriteLimits  WriteLimitsConfig            `yaml:"write-limits"`
+	Redaction    RedactionConfig              `yaml:"redaction"`
 }
 
+// ValidatorConfig describes the validator of a content type. Every validator has its own connection pool,
+// with MaxConcurrentRequests requests at a time, or the default of the service when zero.
+// Drafts of the content type larger than MaxBodySize bytes are rejected, the default of the service applies when zero.
+// UUIDPointer is the JSON pointer of the UUID in the drafts, checked against the UUID they are written under.
 type ValidatorConfig struct {
-	Validator string `yaml:"validator"`
-	Endpoint  string `yaml:"end-point"`
+	Validator             string `yaml:"validator"`
+	Endpoint              string `yaml:"end-point"`
+	MaxConcurrentRequests int    `yaml:"max-concurrent-requests"`
+	MaxBodySize           int64  `yaml:"max-body-size"`
+	UUIDPointer           string `yaml:"uuid-pointer"`
 }
 
+// HealthCheckConfig describes the check of a validator.
+// Criticality is critical (the default) or optional: a failing optional check does not fail the GTG,
+// only the content types validated by the service are reported unavailable.
 type HealthCheckConfig struct {
 	ID               string `yaml:"id"`
 	BusinessImpact   string `yaml:"business-impact"`
@@ -24,6 +37,42 @@ type HealthCheckConfig struct {
 	Severity         uint8  `yaml:"severity"`
 	TechnicalSummary string `yaml:"technical-summary"`
 	CheckerName      string `yaml:"checker-name"`
+	Criticality      string `yaml:"criticality"`
+}
+
+// WebhookConfig describes a partner endpoint notified of draft events.
+// Empty Events or ContentTypes match every event or content type.
+// The secret signing the payloads is expanded from the environment, e.g. ${PARTNER_WEBHOOK_SECRET}.
+type WebhookConfig struct {
+	Name         string   `yaml:"name"`
+	URL          string   `yaml:"url"`
+	Events       []string `yaml:"events"`
+	ContentTypes []string `yaml:"content-types"`
+	Secret       string   `yaml:"secret"`
+}
+
+// WriteLimitsConfig limits the draft writes of every origin system, with the Default limit unless it has its own,
+// and of every draft whatever its origin.
+type WriteLimitsConfig struct {
+	Default WriteLimitConfig            `yaml:"default"`
+	Origins map[string]WriteLimitConfig `yaml:"origins"`
+	PerUUID WriteLimitConfig            `yaml:"per-uuid"`
+}
+
+// WriteLimitConfig is a token bucket refilled with Rate writes per second, holding up to Burst writes,
+// and a maximum number of concurrent writes. Zero values do not limit writes.
+type WriteLimitConfig struct {
+	Rate        float64 `yaml:"rate"`
+	Burst       int     `yaml:"burst"`
+	MaxInFlight int     `yaml:"max-in-flight"`
+}
+
+// RedactionConfig describes what is redacted from the logs, besides credentials.
+// Fields are the names of the log fields redacted whole, matched case-insensitively as substrings.
+// DraftPaths are the JSON pointers of the draft values redacted from the JSON quoted in log messages and fields.
+type RedactionConfig struct {
+	Fields     []string `yaml:"fields"`
+	DraftPaths []string `yaml:"draft-paths"`
 }
 
 func ReadConfig(yml string) (*Config, error) {
@@ -32,11 +81,15 @@ func ReadConfig(yml string) (*Config, error) {
 		return nil, err
 	}
 
-	cfg := &Config{make(map[string]ValidatorConfig), make(map[string]HealthCheckConfig)}
+	cfg := &Config{ContentTypes: make(map[string]ValidatorConfig), HealthChecks: make(map[string]HealthCheckConfig)}
 	err = yaml.Unmarshal(by, cfg)
 	if err != nil {
-		cfg = nil
+		return nil, err
+	}
+
+	for i := range cfg.Webhooks {
+		cfg.Webhooks[i].Secret = os.ExpandEnv(cfg.Webhooks[i].Secret)
 	}
 
-	return cfg, err
+	return cfg, nil
 }
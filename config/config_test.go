@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,3 +28,28 @@ func TestReadConfigNotValid(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, cfg)
 }
+
+func TestReadConfigWebhooks(t *testing.T) {
+	t.Setenv("PARTNER_WEBHOOK_SECRET", "s3cret")
+	yml := filepath.Join(t.TempDir(), "config.yml")
+	err := os.WriteFile(yml, []byte(`
+webhooks:
+  - name: "partner"
+    url: "https://partner.example.com/drafts"
+    events: ["draft-changed"]
+    content-types: ["application/vnd.ft-upp-article+json"]
+    secret: "${PARTNER_WEBHOOK_SECRET}"
+`), 0600)
+	assert.NoError(t, err)
+
+	cfg, err := ReadConfig(yml)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []WebhookConfig{{
+		Name:         "partner",
+		URL:          "https://partner.example.com/drafts",
+		Events:       []string{"draft-changed"},
+		ContentTypes: []string{"application/vnd.ft-upp-article+json"},
+		Secret:       "s3cret",
+	}}, cfg.Webhooks)
+}
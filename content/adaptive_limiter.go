@@ -0,0 +1,133 @@
+package content
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/go-logger/v2"
+)
+
+const (
+	PriorityRead  = "read"
+	PriorityWrite = "write"
+
+	// readLimitShare is the share of the concurrency limit reads may use, the rest is kept for the writes of the editors.
+	readLimitShare = 0.8
+	// limitBackoffRatio decreases the concurrency limit when requests are slower than the latency target.
+	limitBackoffRatio = 0.9
+)
+
+// LoadSheddingObserver is notified of the requests shed by an AdaptiveLimiter and of the changes of its limit.
+type LoadSheddingObserver interface {
+	RequestShed(priority string)
+	ConcurrencyLimitChanged(limit int)
+}
+
+// AdaptiveLimiter sheds requests early when the upstreams slow down, instead of letting them pile up until they time out.
+// Its concurrency limit follows AIMD: it grows by one for every limit requests served within the latency target,
+// and shrinks by a tenth when a request is slower, at most once for the requests admitted under the previous limit.
+// Reads may only use part of the limit, so that writes are shed last.
+type AdaptiveLimiter struct {
+	latencyTarget time.Duration
+	minLimit      float64
+	maxLimit      float64
+	observer      LoadSheddingObserver
+
+	mu           sync.Mutex
+	limit        float64
+	inFlight     int
+	lastDecrease time.Time
+}
+
+// NewAdaptiveLimiter returns a limiter starting at maxLimit concurrent requests. The observer may be nil.
+func NewAdaptiveLimiter(latencyTarget time.Duration, minLimit int, maxLimit int, observer LoadSheddingObserver) *AdaptiveLimiter {
+	if minLimit < 1 {
+		minLimit = 1
+	}
+	if maxLimit < minLimit {
+		maxLimit = minLimit
+	}
+	if observer != nil {
+		observer.ConcurrencyLimitChanged(maxLimit)
+	}
+	return &AdaptiveLimiter{
+		latencyTarget: latencyTarget,
+		minLimit:      float64(minLimit),
+		maxLimit:      float64(maxLimit),
+		observer:      observer,
+		limit:         float64(maxLimit),
+	}
+}
+
+// WithAdaptiveLimiter sheds the reads and writes of the Handler exceeding the limit.
+func WithAdaptiveLimiter(limiter *AdaptiveLimiter) HandlerOption {
+	return func(h *Handler) {
+		h.adaptiveLimiter = limiter
+	}
+}
+
+// admitRequest answers 503 Service Unavailable to a request exceeding the concurrency limit of its priority.
+// The returned function must be called once an admitted request has completed.
+func (h *Handler) admitRequest(w http.ResponseWriter, requestLog *logger.LogEntry, priority string) (func(), bool) {
+	if h.adaptiveLimiter == nil {
+		return func() {}, true
+	}
+
+	release, admitted := h.adaptiveLimiter.acquire(priority)
+	if !admitted {
+		requestLog.WithField("priority", priority).Warn("Request shed, the upstreams are slowing down")
+		w.Header().Set("Retry-After", "1")
+		writeMessage(w, "Service is overloaded, please retry", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return release, true
+}
+
+func (l *AdaptiveLimiter) acquire(priority string) (func(), bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := l.limit
+	if priority == PriorityRead {
+		limit = math.Max(1, limit*readLimitShare)
+	}
+	if float64(l.inFlight) >= math.Floor(limit) {
+		if l.observer != nil {
+			l.observer.RequestShed(priority)
+		}
+		return nil, false
+	}
+
+	l.inFlight++
+	start := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() { l.completed(start) })
+	}, true
+}
+
+func (l *AdaptiveLimiter) completed(start time.Time) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	previous := int(l.limit)
+	switch {
+	case now.Sub(start) > l.latencyTarget:
+		if start.After(l.lastDecrease) {
+			l.limit = math.Max(l.minLimit, l.limit*limitBackoffRatio)
+			l.lastDecrease = now
+		}
+	case float64(l.inFlight+1) >= l.limit/2:
+		// the limit only grows while it is being used
+		l.limit = math.Min(l.maxLimit, l.limit+1/l.limit)
+	}
+
+	if current := int(l.limit); current != previous && l.observer != nil {
+		l.observer.ConcurrencyLimitChanged(current)
+	}
+}
@@ -0,0 +1,154 @@
+package content
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger/v2"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLoadSheddingObserver struct {
+	shed   []string
+	limits []int
+}
+
+func (m *mockLoadSheddingObserver) RequestShed(priority string) {
+	m.shed = append(m.shed, priority)
+}
+
+func (m *mockLoadSheddingObserver) ConcurrencyLimitChanged(limit int) {
+	m.limits = append(m.limits, limit)
+}
+
+func TestAdaptiveLimiterPrioritisesWrites(t *testing.T) {
+	observer := &mockLoadSheddingObserver{}
+	limiter := NewAdaptiveLimiter(time.Minute, 1, 10, observer)
+
+	for i := 0; i < 8; i++ {
+		_, admitted := limiter.acquire(PriorityRead)
+		require.True(t, admitted)
+	}
+	_, admitted := limiter.acquire(PriorityRead)
+	assert.False(t, admitted, "reads only use part of the limit")
+
+	for i := 0; i < 2; i++ {
+		_, admitted := limiter.acquire(PriorityWrite)
+		require.True(t, admitted)
+	}
+	_, admitted = limiter.acquire(PriorityWrite)
+	assert.False(t, admitted)
+
+	assert.Equal(t, []string{PriorityRead, PriorityWrite}, observer.shed)
+	assert.Equal(t, []int{10}, observer.limits)
+}
+
+func TestAdaptiveLimiterBacksOffOnSlowRequests(t *testing.T) {
+	observer := &mockLoadSheddingObserver{}
+	limiter := NewAdaptiveLimiter(time.Nanosecond, 5, 10, observer)
+
+	var releases []func()
+	for i := 0; i < 3; i++ {
+		release, admitted := limiter.acquire(PriorityWrite)
+		require.True(t, admitted)
+		releases = append(releases, release)
+	}
+	time.Sleep(time.Millisecond)
+	for _, release := range releases {
+		release()
+	}
+	assert.Equal(t, 9.0, limiter.limit, "the requests admitted under the previous limit decrease it once")
+
+	for i := 0; i < 20; i++ {
+		release, _ := limiter.acquire(PriorityWrite)
+		time.Sleep(time.Millisecond)
+		release()
+	}
+	assert.Equal(t, 5.0, limiter.limit)
+	assert.Equal(t, []int{10, 9, 8, 7, 6, 5}, observer.limits)
+}
+
+func TestAdaptiveLimiterGrowsWhileUsed(t *testing.T) {
+	limiter := NewAdaptiveLimiter(time.Minute, 1, 10, nil)
+	limiter.limit = 4
+
+	var releases []func()
+	for i := 0; i < 3; i++ {
+		release, _ := limiter.acquire(PriorityWrite)
+		releases = append(releases, release)
+	}
+	for _, release := range releases {
+		release()
+	}
+	assert.Greater(t, limiter.limit, 4.0)
+
+	grown := limiter.limit
+	release, _ := limiter.acquire(PriorityWrite)
+	release()
+	assert.Equal(t, grown, limiter.limit, "an idle limit does not grow")
+}
+
+func TestReadContentShedWhenOverloaded(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, ErrDraftNotFound).Maybe()
+
+	limiter := NewAdaptiveLimiter(time.Minute, 1, 1, nil)
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"), WithAdaptiveLimiter(limiter))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid", h.ReadContent)
+
+	release, admitted := limiter.acquire(PriorityWrite)
+	require.True(t, admitted)
+	defer release()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/drafts/content/%s?source=draft", contentUUID), nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	rw.mock.AssertNotCalled(t, "Read", mock.Anything, contentUUID)
+}
+
+// slowReader reads its draft after a delay, like a client uploading it over a slow connection.
+type slowReader struct {
+	delay time.Duration
+	body  *strings.Reader
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	return r.body.Read(p)
+}
+
+func TestWriteNativeContentSamplesOnlyUpstreamTime(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	draftBody := `{"title":"Draft"}`
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(nil)
+
+	AllowedOriginSystemIDValues = map[string]struct{}{originIDcctTest: {}}
+	AllowedContentTypes = map[string]struct{}{contentTypeArticle: {}}
+	limiter := NewAdaptiveLimiter(50*time.Millisecond, 1, 10, nil)
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"), WithAdaptiveLimiter(limiter))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+
+	req := httptest.NewRequest("PUT", "/drafts/nativecontent/"+contentUUID, &slowReader{100 * time.Millisecond, strings.NewReader(draftBody)})
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, contentTypeArticle)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 10.0, limiter.limit, "a slow upload does not shrink the limit")
+}
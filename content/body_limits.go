@@ -0,0 +1,42 @@
+package content
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WithMaxBodySizes limits the size of the drafts written to the Handler, in bytes, by content type.
+// Content types without a limit, and merge patches, are limited to defaultMaxSize. Zero sizes do not limit drafts.
+func WithMaxBodySizes(defaultMaxSize int64, maxSizes map[string]int64) HandlerOption {
+	return func(h *Handler) {
+		h.defaultMaxBodySize = defaultMaxSize
+		h.maxBodySizes = maxSizes
+	}
+}
+
+// maxBodySize returns the size limit of the drafts of the content type, 0 when they are not limited.
+func (h *Handler) maxBodySize(contentType string) int64 {
+	if limit, found := h.maxBodySizes[stripMediaTypeParameters(contentType)]; found && limit > 0 {
+		return limit
+	}
+	return h.defaultMaxBodySize
+}
+
+// limitBody answers 413 Request Entity Too Large to a request declaring a body over the limit of the content type.
+// Reading more than the limit from the returned body fails with an *http.MaxBytesError.
+func (h *Handler) limitBody(w http.ResponseWriter, r *http.Request, contentType string) (io.Reader, bool) {
+	limit := h.maxBodySize(contentType)
+	if limit <= 0 {
+		return r.Body, true
+	}
+	if r.ContentLength > limit {
+		writeBodyTooLarge(w, limit)
+		return nil, false
+	}
+	return http.MaxBytesReader(w, r.Body, limit), true
+}
+
+func writeBodyTooLarge(w http.ResponseWriter, limit int64) {
+	writeMessage(w, fmt.Sprintf("Draft content body exceeds the limit of %d bytes", limit), http.StatusRequestEntityTooLarge)
+}
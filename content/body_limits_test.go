@@ -0,0 +1,160 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// unsizedReader hides the length of a body, as a chunked request does
+type unsizedReader struct {
+	io.Reader
+}
+
+func newBodyLimitedRouter(rw DraftContentRW) *vestigo.Router {
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+	AllowedContentTypes = map[string]struct{}{
+		contentTypeArticle: {},
+	}
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"),
+		WithMaxBodySizes(16, map[string]int64{contentTypeArticle: 24}))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+	r.Patch("/drafts/nativecontent/:uuid", h.PatchNativeContent)
+	return r
+}
+
+func TestMaxBodySize(t *testing.T) {
+	h := NewHandler(nil, nil, testTimeout, logger.NewUPPLogger("test logger", "debug"),
+		WithMaxBodySizes(16, map[string]int64{contentTypeArticle: 24}))
+
+	assert.Equal(t, int64(24), h.maxBodySize(contentTypeArticle))
+	assert.Equal(t, int64(24), h.maxBodySize(contentTypeArticle+"; charset=utf-8"))
+	assert.Equal(t, int64(16), h.maxBodySize("application/json"))
+
+	unlimited := NewHandler(nil, nil, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	assert.Zero(t, unlimited.maxBodySize(contentTypeArticle))
+}
+
+func TestWriteNativeContentBodyTooLarge(t *testing.T) {
+	tests := map[string]func(body string) io.Reader{
+		"declared length": func(body string) io.Reader { return strings.NewReader(body) },
+		"streamed body":   func(body string) io.Reader { return unsizedReader{strings.NewReader(body)} },
+	}
+
+	for name, reader := range tests {
+		t.Run(name, func(t *testing.T) {
+			contentUUID := uuid.New().String()
+			rw := mockDraftContentRW{}
+			r := newBodyLimitedRouter(&rw)
+
+			req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), reader(`{"title":"A draft over the limit"}`))
+			req.Header.Set(tidutils.TransactionIDHeader, testTID)
+			req.Header.Set(originSystemIdHeader, originIDcctTest)
+			req.Header.Set(contentTypeHeader, contentTypeArticle)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+			assert.Contains(t, w.Body.String(), "exceeds the limit of 24 bytes")
+			rw.mock.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWriteNativeContentWithinBodySizeLimit(t *testing.T) {
+	contentUUID := uuid.New().String()
+	draftBody := `{"title":"Draft"}`
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(nil)
+	r := newBodyLimitedRouter(&rw)
+
+	req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), unsizedReader{strings.NewReader(draftBody)})
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, contentTypeArticle)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "the limit of the content type applies over the default one")
+	rw.mock.AssertExpectations(t)
+}
+
+func TestPatchNativeContentBodyTooLarge(t *testing.T) {
+	tests := map[string]struct {
+		native string
+		patch  string
+		limit  int
+	}{
+		"patch over the default limit": {
+			native: `{"title":"Draft"}`,
+			patch:  `{"title":"A patch over the limit"}`,
+			limit:  16,
+		},
+		"patched draft over the content type limit": {
+			native: `{"title":"Draft","wordCount":120}`,
+			patch:  `{"title":"Long"}`,
+			limit:  24,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			contentUUID := uuid.New().String()
+			metadata := DraftMetadata{ContentType: contentTypeArticle, OriginSystemID: originIDcctTest, WriteReference: "tid_draft"}
+
+			rw := mockDraftContentRW{}
+			rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(test.native)), metadata, nil).Maybe()
+			r := newBodyLimitedRouter(&rw)
+
+			req := httptest.NewRequest("PATCH", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(test.patch))
+			req.Header.Set(tidutils.TransactionIDHeader, testTID)
+			req.Header.Set(originSystemIdHeader, originIDcctTest)
+			req.Header.Set(contentTypeHeader, "application/merge-patch+json")
+			req.Header.Set("If-Match", `"tid_draft"`)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+			assert.Contains(t, w.Body.String(), fmt.Sprintf("exceeds the limit of %d bytes", test.limit))
+			rw.mock.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDraftContentRWWriteReturnsBodyReadErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rw := NewDraftContentRWService(server.URL, nil, server.Client())
+	body := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader(`{"title":"A draft over the limit"}`)), 8)
+	headers := map[string]string{
+		tidutils.TransactionIDHeader: testTID,
+		originSystemIdHeader:         originIDcctTest,
+		contentTypeHeader:            contentTypeArticle,
+	}
+
+	err := rw.Write(context.TODO(), uuid.New().String(), body, headers, logger.NewUPPLogger("test logger", "debug"))
+	require.Error(t, err)
+	var tooLarge *http.MaxBytesError
+	assert.ErrorAs(t, err, &tooLarge, "errors reading the draft are returned as they are")
+}
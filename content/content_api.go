@@ -9,6 +9,7 @@ import (
 
 	"github.com/Financial-Times/go-logger/v2"
 	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -18,17 +19,30 @@ const (
 
 type API struct {
 	endpoint   string
-	username   string
-	password   string
+	auth       RequestAuthenticator
 	xPolicies  []string
 	httpClient *http.Client
 }
 
+// NewContentAPI returns an API authenticating its requests with fixed basic auth credentials.
 func NewContentAPI(endpoint string, username string, password string, xPolicies []string, httpClient *http.Client) *API {
-	return &API{endpoint, username, password, xPolicies, httpClient}
+	return NewAuthenticatedContentAPI(endpoint, NewBasicAuth(StaticCredential(username+":"+password)), xPolicies, httpClient)
 }
 
-func (api *API) Get(ctx context.Context, contentUUID string, log *logger.UPPLogger) (*http.Response, error) {
+// NewAuthenticatedContentAPI returns an API authenticating its requests with auth, e.g. with credentials rotated at runtime.
+func NewAuthenticatedContentAPI(endpoint string, auth RequestAuthenticator, xPolicies []string, httpClient *http.Client) *API {
+	return &API{endpoint, auth, xPolicies, httpClient}
+}
+
+func (api *API) Get(ctx context.Context, contentUUID string, log *logger.UPPLogger) (resp *http.Response, err error) {
+	ctx, span := startSpan(ctx, "API.Get", attribute.String("uuid", contentUUID), attribute.String("endpoint", api.endpoint))
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		}
+		endSpan(span, err)
+	}()
+
 	apiReqURI := api.endpoint + "/" + contentUUID
 	getContentLog := log.WithField("url", apiReqURI).WithField("uuid", contentUUID)
 	tID, err := tidutils.GetTransactionIDFromContext(ctx)
@@ -47,23 +61,34 @@ func (api *API) Get(ctx context.Context, contentUUID string, log *logger.UPPLogg
 		apiReq.Header.Add(xPolicyHeader, policy)
 	}
 
-	apiReq.SetBasicAuth(api.username, api.password)
+	if err = api.auth.Authenticate(apiReq); err != nil {
+		getContentLog.WithError(err).Error("Error in authenticating the http request")
+		return nil, err
+	}
 	if tID != "" {
 		apiReq.Header.Set(tidutils.TransactionIDHeader, tID)
 	}
+	injectTraceContext(ctx, apiReq.Header)
 
 	getContentLog.Info("Calling Content API")
 	return api.httpClient.Do(apiReq.WithContext(ctx))
 }
 
 func (api *API) GTG() error {
+	return api.GTGContext(context.Background())
+}
+
+// GTGContext reads the synthetic content from the Content API until ctx is done.
+func (api *API) GTGContext(ctx context.Context) error {
 	apiReqURI := api.endpoint + "/" + syntheticContentUUID
-	apiReq, err := http.NewRequest("GET", apiReqURI, nil)
+	apiReq, err := http.NewRequestWithContext(ctx, "GET", apiReqURI, nil)
 	if err != nil {
 		return fmt.Errorf("gtg request error: %v", err.Error())
 	}
 
-	apiReq.SetBasicAuth(api.username, api.password)
+	if err = api.auth.Authenticate(apiReq); err != nil {
+		return fmt.Errorf("gtg authentication error: %v", err.Error())
+	}
 
 	apiResp, err := api.httpClient.Do(apiReq)
 	if err != nil {
@@ -72,11 +97,12 @@ func (api *API) GTG() error {
 	defer apiResp.Body.Close()
 
 	if apiResp.StatusCode != http.StatusOK {
-		errMsgBody, err := io.ReadAll(apiResp.Body)
+		// the body is not quoted, upstream error pages may echo credentials or request content
+		size, err := io.Copy(io.Discard, apiResp.Body)
 		if err != nil {
 			return errors.New("gtg returned a non-200 HTTP status")
 		}
-		return fmt.Errorf("gtg returned a non-200 HTTP status: %v - %v", apiResp.StatusCode, string(errMsgBody))
+		return fmt.Errorf("gtg returned a non-200 HTTP status: %v (%d bytes)", apiResp.StatusCode, size)
 	}
 	return nil
 }
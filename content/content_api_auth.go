@@ -0,0 +1,71 @@
+package content
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// RequestAuthenticator authenticates the requests made to the content API.
+type RequestAuthenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// Credential returns the current value of a credential, read for every request so that rotated credentials are used as soon as they are loaded.
+type Credential func() string
+
+// StaticCredential is a credential that never changes.
+func StaticCredential(value string) Credential {
+	return func() string {
+		return value
+	}
+}
+
+var errInvalidBasicAuth = errors.New("basic auth credentials are not of the form username:password")
+
+type basicAuth struct {
+	credentials Credential
+}
+
+// NewBasicAuth authenticates requests with basic auth credentials of the form username:password.
+func NewBasicAuth(credentials Credential) RequestAuthenticator {
+	return &basicAuth{credentials}
+}
+
+func (a *basicAuth) Authenticate(req *http.Request) error {
+	username, password, found := strings.Cut(a.credentials(), ":")
+	if !found {
+		return errInvalidBasicAuth
+	}
+	req.SetBasicAuth(username, password)
+	return nil
+}
+
+type bearerAuth struct {
+	token Credential
+}
+
+// NewBearerAuth authenticates requests with a bearer token in their Authorization header.
+func NewBearerAuth(token Credential) RequestAuthenticator {
+	return &bearerAuth{token}
+}
+
+func (a *bearerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token())
+	return nil
+}
+
+type apiKeyAuth struct {
+	header string
+	key    Credential
+}
+
+// NewAPIKeyAuth authenticates requests with an API key in the given header, e.g. X-Api-Key.
+func NewAPIKeyAuth(header string, key Credential) RequestAuthenticator {
+	return &apiKeyAuth{header, key}
+}
+
+func (a *apiKeyAuth) Authenticate(req *http.Request) error {
+	req.Header.Set(a.header, a.key())
+	return nil
+}
@@ -0,0 +1,62 @@
+package content
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Financial-Times/go-logger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestAuthenticators(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/content", nil)
+	require.NoError(t, NewBasicAuth(StaticCredential("user:pa:ss")).Authenticate(req))
+	username, password, ok := req.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "pa:ss", password)
+
+	req = httptest.NewRequest(http.MethodGet, "/content", nil)
+	require.NoError(t, NewBearerAuth(StaticCredential("a-token")).Authenticate(req))
+	assert.Equal(t, "Bearer a-token", req.Header.Get("Authorization"))
+
+	req = httptest.NewRequest(http.MethodGet, "/content", nil)
+	require.NoError(t, NewAPIKeyAuth("X-Api-Key", StaticCredential("a-key")).Authenticate(req))
+	assert.Equal(t, "a-key", req.Header.Get("X-Api-Key"))
+
+	assert.Equal(t, errInvalidBasicAuth, NewBasicAuth(StaticCredential("no-password")).Authenticate(req))
+}
+
+func TestContentAPIUsesRotatedCredentials(t *testing.T) {
+	var token atomic.Value
+	token.Store("first-token")
+
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	auth := NewBearerAuth(func() string { return token.Load().(string) })
+	cAPI := NewAuthenticatedContentAPI(server.URL, auth, nil, server.Client())
+
+	resp, err := cAPI.Get(context.TODO(), syntheticContentUUID, logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	token.Store("second-token")
+	assert.NoError(t, cAPI.GTG())
+
+	assert.Equal(t, []string{"Bearer first-token", "Bearer second-token"}, authorizations)
+}
+
+func TestContentAPIGTGInvalidCredentials(t *testing.T) {
+	cAPI := NewAuthenticatedContentAPI("http://localhost", NewBasicAuth(StaticCredential("no-password")), nil, http.DefaultClient)
+
+	assert.EqualError(t, cAPI.GTG(), "gtg authentication error: "+errInvalidBasicAuth.Error())
+}
@@ -26,7 +26,7 @@ func TestUnhappyContentAPIGTG(t *testing.T) {
 	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
 	assert.NoError(t, err)
 	cAPI := NewContentAPI(cAPIServerMock.URL+"/content", testBasicAuthUsername, testBasicAuthPassword, nil, testClient)
-	assert.EqualError(t, cAPI.GTG(), "gtg returned a non-200 HTTP status: 503 - I not am happy!")
+	assert.EqualError(t, cAPI.GTG(), "gtg returned a non-200 HTTP status: 503 (15 bytes)")
 }
 
 func TestContentAPIGTGWrongAPIKey(t *testing.T) {
@@ -36,7 +36,7 @@ func TestContentAPIGTGWrongAPIKey(t *testing.T) {
 	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
 	assert.NoError(t, err)
 	cAPI := NewContentAPI(cAPIServerMock.URL+"/content", "a-non-existing-username", "a-non-existing-password", nil, testClient)
-	assert.EqualError(t, cAPI.GTG(), "gtg returned a non-200 HTTP status: 401 - unauthorized")
+	assert.EqualError(t, cAPI.GTG(), "gtg returned a non-200 HTTP status: 401 (12 bytes)")
 }
 
 func TestContentAPIGTGInvalidURL(t *testing.T) {
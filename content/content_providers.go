@@ -0,0 +1,92 @@
+package content
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+)
+
+type fixtureContentProvider struct {
+	dir string
+}
+
+// NewFixtureContentProvider returns a ContentProviderAPI serving published content from <dir>/<uuid>.json files,
+// for environments without access to the UPP delivery cluster.
+func NewFixtureContentProvider(dir string) ContentProviderAPI {
+	return &fixtureContentProvider{dir}
+}
+
+func (p *fixtureContentProvider) Get(ctx context.Context, contentUUID string, log *logger.UPPLogger) (*http.Response, error) {
+	tID, _ := tidutils.GetTransactionIDFromContext(ctx)
+	getContentLog := log.WithField(tidutils.TransactionIDHeader, tID).WithField("uuid", contentUUID)
+
+	if err := validateUUID(contentUUID); err != nil {
+		return newProviderResponse(http.StatusBadRequest, nil), nil
+	}
+
+	fixture := filepath.Join(p.dir, contentUUID+".json")
+	body, err := os.ReadFile(fixture)
+	if errors.Is(err, os.ErrNotExist) {
+		return newProviderResponse(http.StatusNotFound, nil), nil
+	}
+	if err != nil {
+		getContentLog.WithError(err).WithField("fixture", fixture).Error("Error in reading content fixture")
+		return nil, err
+	}
+
+	getContentLog.WithField("fixture", fixture).Info("Serving content fixture")
+	return newProviderResponse(http.StatusOK, body), nil
+}
+
+func (p *fixtureContentProvider) GTG() error {
+	info, err := os.Stat(p.dir)
+	if err != nil {
+		return fmt.Errorf("gtg fixtures error: %v", err.Error())
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("gtg fixtures error: %v is not a directory", p.dir)
+	}
+	return nil
+}
+
+func (p *fixtureContentProvider) Endpoint() string {
+	return p.dir
+}
+
+type disabledContentProvider struct{}
+
+// NewDisabledContentProvider returns a ContentProviderAPI that never finds any published content,
+// so reads only ever return drafts.
+func NewDisabledContentProvider() ContentProviderAPI {
+	return &disabledContentProvider{}
+}
+
+func (p *disabledContentProvider) Get(_ context.Context, _ string, _ *logger.UPPLogger) (*http.Response, error) {
+	return newProviderResponse(http.StatusNotFound, nil), nil
+}
+
+func (p *disabledContentProvider) GTG() error {
+	return nil
+}
+
+func (p *disabledContentProvider) Endpoint() string {
+	return "disabled"
+}
+
+func newProviderResponse(status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Header:        http.Header{contentTypeHeader: []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
@@ -0,0 +1,64 @@
+package content
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixtureContentProvider(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, contentUUID+".json"), []byte(fromUppContent), 0600))
+	ctx := tidutils.TransactionAwareContext(context.TODO(), testTID)
+
+	provider := NewFixtureContentProvider(dir)
+
+	resp, err := provider.Get(ctx, contentUUID, logger.NewUPPLogger("test logger", "debug"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, fromUppContent, string(body))
+
+	assert.NoError(t, provider.GTG())
+	assert.Equal(t, dir, provider.Endpoint())
+}
+
+func TestFixtureContentProviderNotFound(t *testing.T) {
+	provider := NewFixtureContentProvider(t.TempDir())
+
+	resp, err := provider.Get(context.TODO(), "83a201c6-60cd-11e7-91a7-502f7ee26895", logger.NewUPPLogger("test logger", "debug"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestFixtureContentProviderInvalidUUID(t *testing.T) {
+	provider := NewFixtureContentProvider(t.TempDir())
+
+	resp, err := provider.Get(context.TODO(), "../config", logger.NewUPPLogger("test logger", "debug"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestFixtureContentProviderGTGMissingDir(t *testing.T) {
+	provider := NewFixtureContentProvider(filepath.Join(t.TempDir(), "missing"))
+
+	assert.Error(t, provider.GTG())
+}
+
+func TestDisabledContentProvider(t *testing.T) {
+	provider := NewDisabledContentProvider()
+
+	resp, err := provider.Get(context.TODO(), "83a201c6-60cd-11e7-91a7-502f7ee26895", logger.NewUPPLogger("test logger", "debug"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.NoError(t, provider.GTG())
+}
@@ -0,0 +1,139 @@
+package content
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+)
+
+const (
+	AuditActionWrite      = "write"
+	AuditActionPatch      = "patch"
+	AuditActionRead       = "read"
+	AuditActionReadNative = "read-native"
+
+	AuditOutcomeSucceeded = "succeeded"
+	AuditOutcomeRejected  = "rejected"
+	AuditOutcomeFailed    = "failed"
+)
+
+// AuditEvent records a request made on a draft, whatever its outcome.
+// The body hash and size are those of the draft written, they are empty for reads and for writes rejected before the draft was read.
+type AuditEvent struct {
+	Action         string    `json:"action"`
+	UUID           string    `json:"uuid"`
+	OriginSystemID string    `json:"originSystemId,omitempty"`
+	ContentType    string    `json:"contentType,omitempty"`
+	TransactionID  string    `json:"transactionId"`
+	BodyHash       string    `json:"bodyHash,omitempty"`
+	Size           int       `json:"size,omitempty"`
+	Status         int       `json:"status"`
+	Outcome        string    `json:"outcome"`
+	WriteReference string    `json:"writeReference,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// DraftAuditor is notified by the Handler of every draft write, and read, once it has been answered.
+// Implementations must not block, as for DraftObserver.
+type DraftAuditor interface {
+	Audit(ctx context.Context, event AuditEvent)
+}
+
+// WithDraftAuditor registers the auditor of draft requests.
+func WithDraftAuditor(auditor DraftAuditor) HandlerOption {
+	return func(h *Handler) {
+		h.auditor = auditor
+	}
+}
+
+// auditedRequest captures the outcome of a request for the DraftAuditor of the Handler. A nil auditedRequest does nothing.
+type auditedRequest struct {
+	http.ResponseWriter
+	auditor DraftAuditor
+	event   AuditEvent
+}
+
+// startAudit returns the response writer capturing the outcome of the request, and the audit to end once it has been answered.
+func (h *Handler) startAudit(w http.ResponseWriter, r *http.Request, action string, contentUUID string) (http.ResponseWriter, *auditedRequest) {
+	if h.auditor == nil {
+		return w, nil
+	}
+
+	audit := &auditedRequest{
+		ResponseWriter: w,
+		auditor:        h.auditor,
+		event: AuditEvent{
+			Action:        action,
+			UUID:          contentUUID,
+			TransactionID: tidutils.GetTransactionIDFromRequest(r),
+		},
+	}
+	switch action {
+	case AuditActionWrite:
+		audit.event.OriginSystemID = r.Header.Get(originSystemIdHeader)
+		audit.event.ContentType = r.Header.Get(contentTypeHeader)
+	case AuditActionPatch:
+		// the content type of a patch is the one of the stored draft, known once it has been read
+		audit.event.OriginSystemID = r.Header.Get(originSystemIdHeader)
+	}
+	return audit, audit
+}
+
+func (a *auditedRequest) WriteHeader(status int) {
+	if a.event.Status == 0 {
+		a.event.Status = status
+	}
+	a.ResponseWriter.WriteHeader(status)
+}
+
+func (a *auditedRequest) Write(b []byte) (int, error) {
+	if a.event.Status == 0 {
+		a.event.Status = http.StatusOK
+	}
+	return a.ResponseWriter.Write(b)
+}
+
+// draft records the draft being written.
+func (a *auditedRequest) draft(contentType string, draft []byte) {
+	if a == nil {
+		return
+	}
+	hash := sha256.Sum256(draft)
+	a.event.ContentType = contentType
+	a.event.BodyHash = hex.EncodeToString(hash[:])
+	a.event.Size = len(draft)
+}
+
+// metadata records the metadata of the draft read or written.
+func (a *auditedRequest) metadata(metadata DraftMetadata) {
+	if a == nil {
+		return
+	}
+	a.event.OriginSystemID = metadata.OriginSystemID
+	a.event.ContentType = metadata.ContentType
+	a.event.WriteReference = metadata.WriteReference
+}
+
+func (a *auditedRequest) end(ctx context.Context) {
+	if a == nil {
+		return
+	}
+
+	switch {
+	case a.event.Status == 0:
+		a.event.Status = http.StatusOK
+		a.event.Outcome = AuditOutcomeSucceeded
+	case a.event.Status < http.StatusBadRequest:
+		a.event.Outcome = AuditOutcomeSucceeded
+	case a.event.Status < http.StatusInternalServerError:
+		a.event.Outcome = AuditOutcomeRejected
+	default:
+		a.event.Outcome = AuditOutcomeFailed
+	}
+	a.event.Timestamp = time.Now().UTC()
+	a.auditor.Audit(ctx, a.event)
+}
@@ -0,0 +1,134 @@
+package content
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryAuditor struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (a *memoryAuditor) Audit(_ context.Context, event AuditEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.events = append(a.events, event)
+}
+
+func newAuditedRouter(rw DraftContentRW, auditor DraftAuditor) *vestigo.Router {
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+	AllowedContentTypes = map[string]struct{}{
+		contentTypeArticle: {},
+	}
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"), WithDraftAuditor(auditor))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+	r.Patch("/drafts/nativecontent/:uuid", h.PatchNativeContent)
+	r.Get("/drafts/nativecontent/:uuid", h.ReadNativeContent)
+	return r
+}
+
+func TestWriteNativeContentIsAudited(t *testing.T) {
+	contentUUID := uuid.New().String()
+	draftBody := `{"title":"Draft"}`
+	hash := sha256.Sum256([]byte(draftBody))
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(nil).Once()
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(fmt.Errorf("RW unavailable")).Once()
+	auditor := &memoryAuditor{}
+	r := newAuditedRouter(&rw, auditor)
+
+	put := func(originSystemID string) {
+		req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(draftBody))
+		req.Header.Set(tidutils.TransactionIDHeader, testTID)
+		req.Header.Set(originSystemIdHeader, originSystemID)
+		req.Header.Set(contentTypeHeader, contentTypeArticle)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	put(originIDcctTest)
+	put("unknown-origin")
+	put(originIDcctTest)
+
+	require.Len(t, auditor.events, 3)
+	written := auditor.events[0]
+	assert.Equal(t, AuditActionWrite, written.Action)
+	assert.Equal(t, contentUUID, written.UUID)
+	assert.Equal(t, originIDcctTest, written.OriginSystemID)
+	assert.Equal(t, contentTypeArticle, written.ContentType)
+	assert.Equal(t, testTID, written.TransactionID)
+	assert.Equal(t, hex.EncodeToString(hash[:]), written.BodyHash)
+	assert.Equal(t, len(draftBody), written.Size)
+	assert.Equal(t, http.StatusOK, written.Status)
+	assert.Equal(t, AuditOutcomeSucceeded, written.Outcome)
+	assert.Equal(t, testTID, written.WriteReference)
+	assert.False(t, written.Timestamp.IsZero())
+
+	rejected := auditor.events[1]
+	assert.Equal(t, "unknown-origin", rejected.OriginSystemID)
+	assert.Equal(t, http.StatusBadRequest, rejected.Status)
+	assert.Equal(t, AuditOutcomeRejected, rejected.Outcome)
+	assert.Empty(t, rejected.BodyHash)
+
+	failed := auditor.events[2]
+	assert.Equal(t, http.StatusInternalServerError, failed.Status)
+	assert.Equal(t, AuditOutcomeFailed, failed.Outcome)
+	assert.Equal(t, written.BodyHash, failed.BodyHash)
+	assert.Empty(t, failed.WriteReference)
+}
+
+func TestPatchAndReadNativeContentAreAudited(t *testing.T) {
+	contentUUID := uuid.New().String()
+	metadata := DraftMetadata{ContentType: contentTypeArticle, OriginSystemID: originIDcctTest, WriteReference: "tid_draft"}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(`{"title":"Old title"}`)), metadata, nil).Once()
+	rw.mock.On("Write", mock.Anything, contentUUID, `{"title":"New title"}`, mock.Anything).Return(nil)
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(`{"title":"New title"}`)), metadata, nil).Once()
+	auditor := &memoryAuditor{}
+	r := newAuditedRouter(&rw, auditor)
+
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(`{"title":"New title"}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, "application/merge-patch+json")
+	req.Header.Set("If-Match", `"tid_draft"`)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", fmt.Sprintf("/drafts/nativecontent/%s", contentUUID), nil))
+
+	require.Len(t, auditor.events, 2)
+	patched := auditor.events[0]
+	assert.Equal(t, AuditActionPatch, patched.Action)
+	assert.Equal(t, contentTypeArticle, patched.ContentType)
+	assert.Equal(t, len(`{"title":"New title"}`), patched.Size)
+	assert.Equal(t, AuditOutcomeSucceeded, patched.Outcome)
+	assert.Equal(t, testTID, patched.WriteReference)
+
+	read := auditor.events[1]
+	assert.Equal(t, AuditActionReadNative, read.Action)
+	assert.Equal(t, http.StatusOK, read.Status)
+	assert.Equal(t, "tid_draft", read.WriteReference)
+	assert.Empty(t, read.BodyHash)
+}
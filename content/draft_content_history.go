@@ -0,0 +1,22 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/Financial-Times/go-logger/v2"
+)
+
+var ErrDraftVersionNotFound = errors.New("draft version not found")
+
+// DraftContentHistory is implemented by DraftContentRW backends that keep the previous versions of a draft
+// in the store itself, so that every instance of the service sees the same versions, and they survive restarts.
+// Versions are keyed on their write reference, i.e. the Write-Request-Id the store reported for the write.
+// Both the local store and the generic RW keep history, when it is enabled.
+type DraftContentHistory interface {
+	// Versions returns the metadata of the stored versions of a draft, newest first.
+	Versions(ctx context.Context, contentUUID string, log *logger.UPPLogger) ([]DraftMetadata, error)
+	// ReadVersion returns the native body of a single version of a draft.
+	ReadVersion(ctx context.Context, contentUUID string, writeRef string, log *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error)
+}
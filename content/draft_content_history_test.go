@@ -0,0 +1,86 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListDraftVersions(t *testing.T) {
+	contentUUID := uuid.New().String()
+	testLogger := logger.NewUPPLogger("test logger", "debug")
+
+	rw := newTestLocalDraftContentRW(t, nil, 5)
+	body := `{"foo":"bar"}`
+	assert.NoError(t, rw.Write(context.TODO(), contentUUID, strings.NewReader(body), historyTestHeaders(testTID), testLogger))
+
+	h := NewHandler(nil, rw, testTimeout, testLogger)
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/versions", h.ListDraftVersions)
+	r.Get("/drafts/content/:uuid/versions/:writeRequestId", h.ReadDraftVersion)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s/versions", contentUUID), nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	actual, err := io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(actual), `"writeReference":"test_tid"`)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s/versions/%s", contentUUID, testTID), nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	resp = w.Result()
+	actual, err = io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, body, string(actual))
+	assert.Equal(t, contentTypeArticle, resp.Header.Get(contentTypeHeader))
+	assert.Equal(t, originIDcctTest, resp.Header.Get(originSystemIdHeader))
+	assert.Equal(t, testTID, resp.Header.Get("Write-Request-Id"))
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s/versions/unknown", contentUUID), nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+}
+
+func TestListDraftVersionsNotSupported(t *testing.T) {
+	for name, rw := range map[string]DraftContentRW{
+		"generic RW":                  &mockDraftContentRW{},
+		"local store without history": newTestLocalDraftContentRW(t, nil, 0),
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+			r := vestigo.NewRouter()
+			r.Get("/drafts/content/:uuid/versions", h.ListDraftVersions)
+
+			req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s/versions", uuid.New().String()), nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotImplemented, w.Result().StatusCode)
+		})
+	}
+}
+
+func historyTestHeaders(tid string) map[string]string {
+	return map[string]string{
+		tidutils.TransactionIDHeader: tid,
+		originSystemIdHeader:         originIDcctTest,
+		contentTypeHeader:            contentTypeArticle,
+	}
+}
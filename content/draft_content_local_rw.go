@@ -0,0 +1,252 @@
+package content
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	localDraftsBucket   = []byte("drafts")
+	localVersionsBucket = []byte("versions")
+)
+
+type localDraft struct {
+	Body     []byte        `json:"body"`
+	Metadata DraftMetadata `json:"metadata"`
+}
+
+type localDraftContentRW struct {
+	db          *bolt.DB
+	path        string
+	resolver    DraftContentValidatorResolver
+	maxVersions int
+}
+
+// localDraftContentHistoryRW is a local store keeping the previous versions of the drafts.
+type localDraftContentHistoryRW struct {
+	*localDraftContentRW
+}
+
+// NewLocalDraftContentRWService returns a DraftContentRW storing drafts in an embedded database file,
+// so that drafts can be written, read and validated without a generic RW.
+// It keeps the last maxVersions writes of every draft, and implements DraftContentHistory unless maxVersions is zero.
+func NewLocalDraftContentRWService(path string, resolver DraftContentValidatorResolver, maxVersions int) (DraftContentRW, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{localDraftsBucket, localVersionsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	rw := &localDraftContentRW{db, path, resolver, maxVersions}
+	if maxVersions > 0 {
+		return &localDraftContentHistoryRW{rw}, nil
+	}
+	return rw, nil
+}
+
+func (rw *localDraftContentRW) Read(ctx context.Context, contentUUID string, log *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error) {
+	draft, err := rw.readDraft(contentUUID)
+	if err != nil {
+		return nil, DraftMetadata{}, err
+	}
+
+	content, err := validateNativeDraft(ctx, rw.resolver, contentUUID, bytes.NewReader(draft.Body), draft.Metadata, log)
+	return content, draft.Metadata, err
+}
+
+func (rw *localDraftContentRW) ReadNative(_ context.Context, contentUUID string, _ *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error) {
+	draft, err := rw.readDraft(contentUUID)
+	if err != nil {
+		return nil, DraftMetadata{}, err
+	}
+
+	return io.NopCloser(bytes.NewReader(draft.Body)), draft.Metadata, nil
+}
+
+func (rw *localDraftContentRW) readDraft(contentUUID string) (*localDraft, error) {
+	var draft *localDraft
+	err := rw.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(localDraftsBucket).Get([]byte(contentUUID))
+		if value == nil {
+			return ErrDraftNotFound
+		}
+
+		draft = &localDraft{}
+		return json.Unmarshal(value, draft)
+	})
+
+	return draft, err
+}
+
+func (rw *localDraftContentRW) Write(_ context.Context, contentUUID string, content io.Reader, headers map[string]string, log *logger.UPPLogger) error {
+	tid := headers[tidutils.TransactionIDHeader]
+	writeLog := log.WithField(tidutils.TransactionIDHeader, tid).WithField("uuid", contentUUID)
+
+	body, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(localDraft{
+		Body: body,
+		Metadata: DraftMetadata{
+			ContentType:    headers[contentTypeHeader],
+			OriginSystemID: headers[originSystemIdHeader],
+			LastModified:   time.Now().UTC().Format(time.RFC3339),
+			WriteReference: tid,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = rw.db.Update(func(tx *bolt.Tx) error {
+		if draftRef := headers[ifMatchHeader]; draftRef != "" {
+			var current localDraft
+			stored := tx.Bucket(localDraftsBucket).Get([]byte(contentUUID))
+			if stored == nil {
+				return ErrDraftModified
+			}
+			if err := json.Unmarshal(stored, &current); err != nil {
+				return err
+			}
+			if current.Metadata.WriteReference != draftRef {
+				return ErrDraftModified
+			}
+		}
+		if err := tx.Bucket(localDraftsBucket).Put([]byte(contentUUID), value); err != nil {
+			return err
+		}
+		return rw.addVersion(tx, contentUUID, value)
+	})
+	if err != nil && err != ErrDraftModified {
+		writeLog.WithError(err).Error("Error writing draft content to local store")
+	}
+
+	return err
+}
+
+func (rw *localDraftContentRW) addVersion(tx *bolt.Tx, contentUUID string, value []byte) error {
+	if rw.maxVersions <= 0 {
+		return nil
+	}
+
+	versions, err := tx.Bucket(localVersionsBucket).CreateBucketIfNotExists([]byte(contentUUID))
+	if err != nil {
+		return err
+	}
+
+	seq, err := versions.NextSequence()
+	if err != nil {
+		return err
+	}
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	if err = versions.Put(key, value); err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	c := versions.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	for i := 0; i < len(keys)-rw.maxVersions; i++ {
+		if err = versions.Delete(keys[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rw *localDraftContentHistoryRW) Versions(_ context.Context, contentUUID string, _ *logger.UPPLogger) ([]DraftMetadata, error) {
+	var result []DraftMetadata
+	err := rw.db.View(func(tx *bolt.Tx) error {
+		versions := tx.Bucket(localVersionsBucket).Bucket([]byte(contentUUID))
+		if versions == nil {
+			return ErrDraftNotFound
+		}
+
+		c := versions.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var draft localDraft
+			if err := json.Unmarshal(v, &draft); err != nil {
+				return err
+			}
+			result = append(result, draft.Metadata)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+func (rw *localDraftContentHistoryRW) ReadVersion(_ context.Context, contentUUID string, writeRef string, _ *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error) {
+	var found *localDraft
+	err := rw.db.View(func(tx *bolt.Tx) error {
+		versions := tx.Bucket(localVersionsBucket).Bucket([]byte(contentUUID))
+		if versions == nil {
+			return ErrDraftVersionNotFound
+		}
+
+		c := versions.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var draft localDraft
+			if err := json.Unmarshal(v, &draft); err != nil {
+				return err
+			}
+			if draft.Metadata.WriteReference == writeRef {
+				found = &draft
+				return nil
+			}
+		}
+		return ErrDraftVersionNotFound
+	})
+	if err != nil {
+		return nil, DraftMetadata{}, err
+	}
+
+	return io.NopCloser(bytes.NewReader(found.Body)), found.Metadata, nil
+}
+
+func (rw *localDraftContentRW) GTG() error {
+	err := rw.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(localDraftsBucket) == nil {
+			return fmt.Errorf("missing %s bucket", localDraftsBucket)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("gtg local draft store error: %v", err.Error())
+	}
+	return nil
+}
+
+func (rw *localDraftContentRW) Endpoint() string {
+	return rw.path
+}
+
+func (rw *localDraftContentRW) Close() error {
+	return rw.db.Close()
+}
@@ -0,0 +1,149 @@
+package content
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLocalDraftContentRWWriteAndRead(t *testing.T) {
+	contentUUID := uuid.New().String()
+	content := "{\"foo\":\"bar\"}"
+	expectedContent := []byte("{\"foo\":\"baz\"}")
+	ctx := tidutils.TransactionAwareContext(context.TODO(), testTID)
+	testLogger := logger.NewUPPLogger("test logger", "debug")
+
+	validator := mockContentValidator(t, "", testTID)
+	validator.mock.On("Validate", mock.Anything, contentUUID, mock.Anything, contentTypeArticle).Return(io.NopCloser(bytes.NewReader(expectedContent)), nil)
+
+	rw := newTestLocalDraftContentRW(t, NewDraftContentValidatorResolver(cctOnlyResolverConfig(validator)), 5)
+
+	err := rw.Write(ctx, contentUUID, strings.NewReader(content), historyTestHeaders(testTID), testLogger)
+	assert.NoError(t, err)
+
+	body, metadata, err := rw.Read(ctx, contentUUID, testLogger)
+	assert.NoError(t, err)
+	actual, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedContent, actual, "content")
+	assert.Equal(t, contentTypeArticle, metadata.ContentType)
+	assert.Equal(t, originIDcctTest, metadata.OriginSystemID)
+	assert.Equal(t, testTID, metadata.WriteReference)
+	assert.NotEmpty(t, metadata.LastModified)
+
+	native, nativeMetadata, err := rw.ReadNative(ctx, contentUUID, testLogger)
+	assert.NoError(t, err)
+	actual, err = io.ReadAll(native)
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(actual), "native content")
+	assert.Equal(t, metadata, nativeMetadata)
+	validator.mock.AssertExpectations(t)
+}
+
+func TestLocalDraftContentRWNotFound(t *testing.T) {
+	testLogger := logger.NewUPPLogger("test logger", "debug")
+	rw := newTestLocalDraftContentRW(t, nil, 5)
+
+	_, _, err := rw.Read(context.TODO(), uuid.New().String(), testLogger)
+	assert.Equal(t, ErrDraftNotFound, err)
+
+	_, _, err = rw.ReadNative(context.TODO(), uuid.New().String(), testLogger)
+	assert.Equal(t, ErrDraftNotFound, err)
+
+	_, err = rw.(DraftContentHistory).Versions(context.TODO(), uuid.New().String(), testLogger)
+	assert.Equal(t, ErrDraftNotFound, err)
+}
+
+func TestLocalDraftContentRWValidatorError(t *testing.T) {
+	contentUUID := uuid.New().String()
+	content := "{\"foo\":\"bar\"}"
+	testLogger := logger.NewUPPLogger("test logger", "debug")
+
+	validator := mockContentValidator(t, "", "")
+	validator.mock.On("Validate", mock.Anything, contentUUID, mock.Anything, contentTypeArticle).Return(nil, ValidatorError{422, "test validator error"})
+
+	rw := newTestLocalDraftContentRW(t, NewDraftContentValidatorResolver(cctOnlyResolverConfig(validator)), 5)
+	assert.NoError(t, rw.Write(context.TODO(), contentUUID, strings.NewReader(content), historyTestHeaders(testTID), testLogger))
+
+	body, _, err := rw.Read(context.TODO(), contentUUID, testLogger)
+	assert.Equal(t, ErrDraftNotValid, err)
+	assert.Nil(t, body)
+}
+
+func TestLocalDraftContentRWVersions(t *testing.T) {
+	contentUUID := uuid.New().String()
+	testLogger := logger.NewUPPLogger("test logger", "debug")
+	rw := newTestLocalDraftContentRW(t, nil, 2)
+
+	for i := 1; i <= 3; i++ {
+		body := fmt.Sprintf(`{"version":%d}`, i)
+		assert.NoError(t, rw.Write(context.TODO(), contentUUID, strings.NewReader(body), historyTestHeaders(fmt.Sprintf("tid_%d", i)), testLogger))
+	}
+
+	history := rw.(DraftContentHistory)
+	versions, err := history.Versions(context.TODO(), contentUUID, testLogger)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "tid_3", versions[0].WriteReference)
+	assert.Equal(t, "tid_2", versions[1].WriteReference)
+
+	body, metadata, err := history.ReadVersion(context.TODO(), contentUUID, "tid_2", testLogger)
+	assert.NoError(t, err)
+	actual, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"version":2}`, string(actual))
+	assert.Equal(t, "tid_2", metadata.WriteReference)
+
+	_, _, err = history.ReadVersion(context.TODO(), contentUUID, "tid_1", testLogger)
+	assert.Equal(t, ErrDraftVersionNotFound, err)
+}
+
+func TestLocalDraftContentRWConditionalWrite(t *testing.T) {
+	contentUUID := uuid.New().String()
+	testLogger := logger.NewUPPLogger("test logger", "debug")
+	rw := newTestLocalDraftContentRW(t, nil, 0)
+
+	conditional := historyTestHeaders("tid_2")
+	conditional[ifMatchHeader] = "tid_1"
+	assert.Equal(t, ErrDraftModified, rw.Write(context.TODO(), contentUUID, strings.NewReader(`{"version":2}`), conditional, testLogger), "missing draft")
+
+	assert.NoError(t, rw.Write(context.TODO(), contentUUID, strings.NewReader(`{"version":1}`), historyTestHeaders("tid_1"), testLogger))
+	assert.NoError(t, rw.Write(context.TODO(), contentUUID, strings.NewReader(`{"version":2}`), conditional, testLogger))
+
+	conditional = historyTestHeaders("tid_3")
+	conditional[ifMatchHeader] = "tid_1"
+	assert.Equal(t, ErrDraftModified, rw.Write(context.TODO(), contentUUID, strings.NewReader(`{"version":3}`), conditional, testLogger), "stale draft reference")
+
+	_, metadata, err := rw.ReadNative(context.TODO(), contentUUID, testLogger)
+	assert.NoError(t, err)
+	assert.Equal(t, "tid_2", metadata.WriteReference)
+}
+
+func TestLocalDraftContentRWGTG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drafts.db")
+	rw, err := NewLocalDraftContentRWService(path, nil, 0)
+	assert.NoError(t, err)
+	defer rw.(io.Closer).Close()
+
+	assert.NoError(t, rw.GTG())
+	assert.Equal(t, path, rw.Endpoint())
+}
+
+func newTestLocalDraftContentRW(t *testing.T, resolver DraftContentValidatorResolver, maxVersions int) DraftContentRW {
+	rw, err := NewLocalDraftContentRWService(filepath.Join(t.TempDir(), "drafts.db"), resolver, maxVersions)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, rw.(io.Closer).Close())
+	})
+	return rw
+}
@@ -12,21 +12,38 @@ import (
 	"github.com/Financial-Times/draft-content-api/platform"
 	"github.com/Financial-Times/go-logger/v2"
 	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
 	rwURLPattern = "%s/drafts/content/%s"
+
+	lastModifiedHeader   = "Last-Modified-RFC3339"
+	writeRequestIDHeader = "Write-Request-Id"
 )
 
 var (
 	ErrDraftNotFound                = errors.New("draft content not found in PAC")
 	ErrDraftNotValid                = errors.New("draft content is invalid")
 	ErrDraftContentTypeNotSupported = errors.New("draft content-type is invalid")
+	ErrDraftModified                = errors.New("draft content has been modified")
 )
 
+// DraftMetadata describes how a native draft is stored in the content RW.
+type DraftMetadata struct {
+	ContentType    string `json:"contentType"`
+	OriginSystemID string `json:"originSystemId"`
+	LastModified   string `json:"lastModified"`
+	WriteReference string `json:"writeReference"`
+}
+
 type DraftContentRW interface {
-	Read(ctx context.Context, contentUUID string, log *logger.UPPLogger) (io.ReadCloser, error)
-	Write(ctx context.Context, contentUUID string, content *string, headers map[string]string, log *logger.UPPLogger) error
+	Read(ctx context.Context, contentUUID string, log *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error)
+	ReadNative(ctx context.Context, contentUUID string, log *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error)
+	// Write stores the draft read from content, which is streamed rather than buffered when possible.
+	// When the headers have an If-Match draft reference, the draft is only stored if it is still the current one,
+	// and ErrDraftModified is returned otherwise.
+	Write(ctx context.Context, contentUUID string, content io.Reader, headers map[string]string, log *logger.UPPLogger) error
 	GTG() error
 	Endpoint() string
 }
@@ -41,60 +58,108 @@ func NewDraftContentRWService(endpoint string, resolver DraftContentValidatorRes
 	return &draftContentRW{s, resolver}
 }
 
-func (rw *draftContentRW) Read(ctx context.Context, contentUUID string, log *logger.UPPLogger) (io.ReadCloser, error) {
+func (rw *draftContentRW) Read(ctx context.Context, contentUUID string, log *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error) {
 	tid, _ := tidutils.GetTransactionIDFromContext(ctx)
 	readLog := log.WithField(tidutils.TransactionIDHeader, tid).WithField("uuid", contentUUID)
 
 	resp, err := rw.readNativeContent(ctx, contentUUID, log)
 	if err != nil {
 		readLog.WithError(err).Error("Error making the HTTP request to content RW")
-		return nil, err
+		return nil, DraftMetadata{}, err
 	}
 	defer resp.Body.Close()
 	var content io.ReadCloser
+	var metadata DraftMetadata
 	switch resp.StatusCode {
 	case http.StatusOK:
-		var nativeContent io.Reader
-		nativeContent, err = rw.constructNativeDocumentForValidator(ctx, resp.Body, resp.Header.Get("Last-Modified-RFC3339"), resp.Header.Get("Write-Request-Id"), log)
+		metadata = draftMetadataFromHeader(resp.Header)
+		content, err = validateNativeDraft(ctx, rw.resolver, contentUUID, resp.Body, metadata, log)
+	case http.StatusNotFound:
+		err = ErrDraftNotFound
+	default:
+		return nil, DraftMetadata{}, fmt.Errorf("content RW returned an unexpected HTTP status code in read operation: %v", resp.StatusCode)
+	}
 
-		if err == nil {
-			contentType := resp.Header.Get(contentTypeHeader)
-			validator, resolverErr := rw.resolver.ValidatorForContentType(contentType)
+	return content, metadata, err
+}
 
-			if resolverErr != nil {
-				readLog.WithError(resolverErr).Error("Unable to validate content")
-				return nil, resolverErr
-			}
+// validateNativeDraft maps a stored native draft into UPP format through the validator configured for its content type.
+func validateNativeDraft(ctx context.Context, resolver DraftContentValidatorResolver, contentUUID string, nativeBody io.Reader, metadata DraftMetadata, log *logger.UPPLogger) (io.ReadCloser, error) {
+	tid, _ := tidutils.GetTransactionIDFromContext(ctx)
+	readLog := log.WithField(tidutils.TransactionIDHeader, tid).WithField("uuid", contentUUID)
 
-			content, err = validator.Validate(ctx, contentUUID, nativeContent, contentType, log)
-
-			if err != nil {
-				readLog.WithError(err).Warn("Validator error")
-				var validatorError ValidatorError
-				if errors.As(err, &validatorError) {
-					switch validatorError.StatusCode() {
-					case http.StatusNotFound:
-						fallthrough
-					case http.StatusUnsupportedMediaType:
-						err = ErrDraftContentTypeNotSupported
-					case http.StatusUnprocessableEntity:
-						err = ErrDraftNotValid
-					}
-				}
+	nativeContent, err := constructNativeDocumentForValidator(ctx, nativeBody, metadata.LastModified, metadata.WriteReference, log)
+	if err != nil {
+		readLog.WithError(err).Warn("Error constructing validator input")
+		return nil, err
+	}
+
+	validator, err := resolver.ValidatorForContentType(metadata.ContentType)
+	if err != nil {
+		readLog.WithError(err).Error("Unable to validate content")
+		return nil, err
+	}
+
+	content, err := validator.Validate(ctx, contentUUID, nativeContent, metadata.ContentType, log)
+	if err != nil {
+		readLog.WithError(err).Warn("Validator error")
+		var validatorError ValidatorError
+		if errors.As(err, &validatorError) {
+			switch validatorError.StatusCode() {
+			case http.StatusNotFound:
+				fallthrough
+			case http.StatusUnsupportedMediaType:
+				err = ErrDraftContentTypeNotSupported
+			case http.StatusUnprocessableEntity:
+				err = ErrDraftNotValid
 			}
-		} else {
-			readLog.WithError(err).Warn("Error constructing validator input")
 		}
-	case http.StatusNotFound:
-		err = ErrDraftNotFound
-	default:
-		return nil, fmt.Errorf("content RW returned an unexpected HTTP status code in read operation: %v", resp.StatusCode)
 	}
 
 	return content, err
 }
 
-func (rw *draftContentRW) readNativeContent(ctx context.Context, contentUUID string, log *logger.UPPLogger) (*http.Response, error) {
+func draftMetadataFromHeader(header http.Header) DraftMetadata {
+	return DraftMetadata{
+		ContentType:    header.Get(contentTypeHeader),
+		OriginSystemID: header.Get(originSystemIdHeader),
+		LastModified:   header.Get(lastModifiedHeader),
+		WriteReference: header.Get(writeRequestIDHeader),
+	}
+}
+
+// ReadNative returns the draft as it was written, without validating or mapping it.
+func (rw *draftContentRW) ReadNative(ctx context.Context, contentUUID string, log *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error) {
+	tid, _ := tidutils.GetTransactionIDFromContext(ctx)
+	readLog := log.WithField(tidutils.TransactionIDHeader, tid).WithField("uuid", contentUUID)
+
+	resp, err := rw.readNativeContent(ctx, contentUUID, log)
+	if err != nil {
+		readLog.WithError(err).Error("Error making the HTTP request to content RW")
+		return nil, DraftMetadata{}, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, draftMetadataFromHeader(resp.Header), nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, DraftMetadata{}, ErrDraftNotFound
+	default:
+		resp.Body.Close()
+		return nil, DraftMetadata{}, fmt.Errorf("content RW returned an unexpected HTTP status code in read operation: %v", resp.StatusCode)
+	}
+}
+
+func (rw *draftContentRW) readNativeContent(ctx context.Context, contentUUID string, log *logger.UPPLogger) (resp *http.Response, err error) {
+	ctx, span := startSpan(ctx, "draftContentRW.readNativeContent", attribute.String("uuid", contentUUID))
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		}
+		endSpan(span, err)
+	}()
+
 	tid, _ := tidutils.GetTransactionIDFromContext(ctx)
 	readLog := log.WithField(tidutils.TransactionIDHeader, tid).WithField("uuid", contentUUID)
 
@@ -107,12 +172,15 @@ func (rw *draftContentRW) readNativeContent(ctx context.Context, contentUUID str
 	return rw.HTTPClient().Do(req)
 }
 
-func (rw *draftContentRW) constructNativeDocumentForValidator(ctx context.Context, rawNativeBody io.Reader, lastModified string, writeRef string, log *logger.UPPLogger) (io.Reader, error) {
+func constructNativeDocumentForValidator(ctx context.Context, rawNativeBody io.Reader, lastModified string, writeRef string, log *logger.UPPLogger) (_ io.Reader, err error) {
+	_, span := startSpan(ctx, "constructNativeDocumentForValidator")
+	defer func() { endSpan(span, err) }()
+
 	tid, _ := tidutils.GetTransactionIDFromContext(ctx)
 	readLog := log.WithField(tidutils.TransactionIDHeader, tid)
 
 	rawNativeDoc := make(map[string]interface{})
-	err := json.NewDecoder(rawNativeBody).Decode(&rawNativeDoc)
+	err = json.NewDecoder(rawNativeBody).Decode(&rawNativeDoc)
 	if err != nil {
 		readLog.WithError(err).Error("unable to unmarshal native content")
 		return nil, err
@@ -130,12 +198,19 @@ func (rw *draftContentRW) constructNativeDocumentForValidator(ctx context.Contex
 	return bytes.NewReader(nativeDoc), nil
 }
 
-func (rw *draftContentRW) Write(ctx context.Context, contentUUID string, content *string, headers map[string]string, log *logger.UPPLogger) error {
+func (rw *draftContentRW) Write(ctx context.Context, contentUUID string, content io.Reader, headers map[string]string, log *logger.UPPLogger) error {
 	tid := headers[tidutils.TransactionIDHeader]
 
 	writeLog := log.WithField(tidutils.TransactionIDHeader, tid).WithField("uuid", contentUUID)
 
-	req, err := newHttpRequest(ctx, "PUT", fmt.Sprintf(rwURLPattern, rw.Endpoint(), contentUUID), bytes.NewBuffer([]byte(*content)))
+	return rw.put(ctx, fmt.Sprintf(rwURLPattern, rw.Endpoint(), contentUUID), content, headers, writeLog)
+}
+
+// put stores the document at the RW URL, conditionally on the If-Match reference of the headers, if any.
+func (rw *draftContentRW) put(ctx context.Context, url string, content io.Reader, headers map[string]string, writeLog *logger.LogEntry) error {
+	tid := headers[tidutils.TransactionIDHeader]
+
+	req, err := newHttpRequest(ctx, "PUT", url, content)
 	if err != nil {
 		writeLog.WithError(err).Error("Error in creating the HTTP write request to content RW")
 		return err
@@ -143,6 +218,9 @@ func (rw *draftContentRW) Write(ctx context.Context, contentUUID string, content
 	req.Header.Set(tidutils.TransactionIDHeader, tid)
 	req.Header.Set(originSystemIdHeader, headers[originSystemIdHeader])
 	req.Header.Set(contentTypeHeader, headers[contentTypeHeader])
+	if draftRef := headers[ifMatchHeader]; draftRef != "" {
+		req.Header.Set(ifMatchHeader, `"`+draftRef+`"`)
+	}
 
 	resp, err := rw.HTTPClient().Do(req)
 	if err != nil {
@@ -154,6 +232,8 @@ func (rw *draftContentRW) Write(ctx context.Context, contentUUID string, content
 	switch resp.StatusCode {
 	case http.StatusOK, http.StatusCreated:
 		return nil
+	case http.StatusPreconditionFailed:
+		return ErrDraftModified
 	default:
 		return fmt.Errorf("content RW returned an unexpected HTTP status code in write operation: %v", resp.StatusCode)
 	}
@@ -0,0 +1,220 @@
+package content
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+)
+
+const (
+	rwVersionsURLPattern = "%s/drafts/content-versions/%s"
+
+	// maxIndexWriteAttempts bounds the retries of the version index writes conflicting with another instance
+	maxIndexWriteAttempts = 3
+)
+
+// draftVersionNamespace derives the keys of the versions in the RW from the draft UUID and the write reference.
+var draftVersionNamespace = uuid.MustParse("0a3c5e8b-6f1d-4b2a-9c7e-5d4f3b2a1e0c")
+
+// draftVersionIndex lists the versions of a draft kept by the RW, newest first.
+type draftVersionIndex struct {
+	Versions []DraftMetadata `json:"versions"`
+}
+
+// draftContentHistoryRW is a generic RW keeping the previous versions of the drafts in the drafts/content-versions
+// collection of the same RW: every version under a key derived from its write reference,
+// and the index of the versions of a draft under its UUID.
+type draftContentHistoryRW struct {
+	*draftContentRW
+	maxVersions int
+}
+
+// NewDraftContentHistoryRWService returns a generic RW DraftContentRW which keeps the last maxVersions writes of every draft,
+// and implements DraftContentHistory unless maxVersions is zero.
+func NewDraftContentHistoryRWService(endpoint string, resolver DraftContentValidatorResolver, httpClient *http.Client, maxVersions int) DraftContentRW {
+	rw := NewDraftContentRWService(endpoint, resolver, httpClient).(*draftContentRW)
+	if maxVersions <= 0 {
+		return rw
+	}
+	return &draftContentHistoryRW{rw, maxVersions}
+}
+
+// Write stores the draft, then adds it to its versions. The draft is written even if its version cannot be kept.
+func (rw *draftContentHistoryRW) Write(ctx context.Context, contentUUID string, content io.Reader, headers map[string]string, log *logger.UPPLogger) error {
+	// drafts are already buffered by the Handler, so that they are checked before they are written
+	body, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	if err = rw.draftContentRW.Write(ctx, contentUUID, bytes.NewReader(body), headers, log); err != nil {
+		return err
+	}
+
+	writeLog := log.WithField(tidutils.TransactionIDHeader, headers[tidutils.TransactionIDHeader]).WithField("uuid", contentUUID)
+	if err = rw.addVersion(ctx, contentUUID, body, headers, writeLog); err != nil {
+		writeLog.WithError(err).Error("Unable to keep the version of the draft, it is written but missing from its history")
+	}
+	return nil
+}
+
+func (rw *draftContentHistoryRW) addVersion(ctx context.Context, contentUUID string, body []byte, headers map[string]string, writeLog *logger.LogEntry) error {
+	version := DraftMetadata{
+		ContentType:    headers[contentTypeHeader],
+		OriginSystemID: headers[originSystemIdHeader],
+		LastModified:   time.Now().UTC().Format(time.RFC3339),
+		WriteReference: headers[tidutils.TransactionIDHeader],
+	}
+
+	versionHeaders := map[string]string{
+		tidutils.TransactionIDHeader: version.WriteReference,
+		originSystemIdHeader:         version.OriginSystemID,
+		contentTypeHeader:            version.ContentType,
+	}
+	if err := rw.put(ctx, rw.versionURL(contentUUID, version.WriteReference), bytes.NewReader(body), versionHeaders, writeLog); err != nil {
+		return err
+	}
+
+	for attempt := 0; attempt < maxIndexWriteAttempts; attempt++ {
+		index, indexRef, err := rw.readIndex(ctx, contentUUID)
+		if err != nil && err != ErrDraftNotFound {
+			return err
+		}
+
+		index.Versions = append([]DraftMetadata{version}, index.Versions...)
+		var pruned []DraftMetadata
+		if len(index.Versions) > rw.maxVersions {
+			pruned = index.Versions[rw.maxVersions:]
+			index.Versions = index.Versions[:rw.maxVersions]
+		}
+
+		value, err := json.Marshal(index)
+		if err != nil {
+			return err
+		}
+
+		indexHeaders := map[string]string{
+			tidutils.TransactionIDHeader: version.WriteReference,
+			originSystemIdHeader:         version.OriginSystemID,
+			contentTypeHeader:            "application/json",
+			ifMatchHeader:                indexRef,
+		}
+		err = rw.put(ctx, fmt.Sprintf(rwVersionsURLPattern, rw.Endpoint(), contentUUID), bytes.NewReader(value), indexHeaders, writeLog)
+		if err == ErrDraftModified {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, old := range pruned {
+			if err = rw.delete(ctx, rw.versionURL(contentUUID, old.WriteReference)); err != nil {
+				writeLog.WithError(err).WithField("writeReference", old.WriteReference).Warn("Unable to delete a pruned version of the draft")
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("version index of the draft modified concurrently %d times", maxIndexWriteAttempts)
+}
+
+func (rw *draftContentHistoryRW) Versions(ctx context.Context, contentUUID string, _ *logger.UPPLogger) ([]DraftMetadata, error) {
+	index, _, err := rw.readIndex(ctx, contentUUID)
+	if err != nil {
+		return nil, err
+	}
+	return index.Versions, nil
+}
+
+// ReadVersion reads the versions listed in the index of the draft only, so that pruned versions are not returned.
+func (rw *draftContentHistoryRW) ReadVersion(ctx context.Context, contentUUID string, writeRef string, _ *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error) {
+	index, _, err := rw.readIndex(ctx, contentUUID)
+	if err == ErrDraftNotFound {
+		return nil, DraftMetadata{}, ErrDraftVersionNotFound
+	}
+	if err != nil {
+		return nil, DraftMetadata{}, err
+	}
+
+	for _, version := range index.Versions {
+		if version.WriteReference != writeRef {
+			continue
+		}
+
+		resp, err := rw.get(ctx, rw.versionURL(contentUUID, writeRef))
+		if err != nil {
+			return nil, DraftMetadata{}, err
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return resp.Body, version, nil
+		case http.StatusNotFound:
+			resp.Body.Close()
+			return nil, DraftMetadata{}, ErrDraftVersionNotFound
+		default:
+			resp.Body.Close()
+			return nil, DraftMetadata{}, fmt.Errorf("content RW returned an unexpected HTTP status code in version read operation: %v", resp.StatusCode)
+		}
+	}
+	return nil, DraftMetadata{}, ErrDraftVersionNotFound
+}
+
+// readIndex returns the version index of the draft, with its write reference for a conditional update.
+func (rw *draftContentHistoryRW) readIndex(ctx context.Context, contentUUID string) (draftVersionIndex, string, error) {
+	var index draftVersionIndex
+	resp, err := rw.get(ctx, fmt.Sprintf(rwVersionsURLPattern, rw.Endpoint(), contentUUID))
+	if err != nil {
+		return index, "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		err = json.NewDecoder(resp.Body).Decode(&index)
+		return index, resp.Header.Get(writeRequestIDHeader), err
+	case http.StatusNotFound:
+		return index, "", ErrDraftNotFound
+	default:
+		return index, "", fmt.Errorf("content RW returned an unexpected HTTP status code in version index read operation: %v", resp.StatusCode)
+	}
+}
+
+func (rw *draftContentHistoryRW) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := newHttpRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return rw.HTTPClient().Do(req)
+}
+
+func (rw *draftContentHistoryRW) delete(ctx context.Context, url string) error {
+	req, err := newHttpRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := rw.HTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("content RW returned an unexpected HTTP status code in delete operation: %v", resp.StatusCode)
+	}
+}
+
+func (rw *draftContentHistoryRW) versionURL(contentUUID string, writeRef string) string {
+	versionUUID := uuid.NewSHA1(draftVersionNamespace, []byte(contentUUID+"/"+writeRef))
+	return fmt.Sprintf(rwVersionsURLPattern, rw.Endpoint(), versionUUID)
+}
@@ -0,0 +1,121 @@
+package content
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type storedDocument struct {
+	body    []byte
+	headers http.Header
+}
+
+// genericRW is an in memory generic RW, serving conditional writes.
+type genericRW struct {
+	mu        sync.Mutex
+	documents map[string]storedDocument
+}
+
+func (rw *genericRW) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	doc, found := rw.documents[r.URL.Path]
+	switch r.Method {
+	case http.MethodGet:
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		for key, values := range doc.headers {
+			w.Header()[key] = values
+		}
+		w.Write(doc.body)
+	case http.MethodPut:
+		if ifMatch := strings.Trim(r.Header.Get(ifMatchHeader), `"`); ifMatch != "" && (!found || doc.headers.Get(writeRequestIDHeader) != ifMatch) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		rw.documents[r.URL.Path] = storedDocument{body, http.Header{
+			contentTypeHeader:    {r.Header.Get(contentTypeHeader)},
+			originSystemIdHeader: {r.Header.Get(originSystemIdHeader)},
+			writeRequestIDHeader: {r.Header.Get(tidutils.TransactionIDHeader)},
+		}}
+	case http.MethodDelete:
+		delete(rw.documents, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (rw *genericRW) count() int {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	return len(rw.documents)
+}
+
+func writeDraftVersion(t *testing.T, rw DraftContentRW, contentUUID string, tid string, body string) {
+	headers := map[string]string{
+		tidutils.TransactionIDHeader: tid,
+		originSystemIdHeader:         originIDcctTest,
+		contentTypeHeader:            contentTypeArticle,
+	}
+	require.NoError(t, rw.Write(context.TODO(), contentUUID, strings.NewReader(body), headers, logger.NewUPPLogger("test logger", "debug")))
+}
+
+func TestGenericRWKeepsDraftVersions(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	store := &genericRW{documents: map[string]storedDocument{}}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	rw := NewDraftContentHistoryRWService(server.URL, nil, server.Client(), 2)
+	history, ok := rw.(DraftContentHistory)
+	require.True(t, ok)
+
+	writeDraftVersion(t, rw, contentUUID, "tid_1", `{"title":"First"}`)
+	writeDraftVersion(t, rw, contentUUID, "tid_2", `{"title":"Second"}`)
+	writeDraftVersion(t, rw, contentUUID, "tid_3", `{"title":"Third"}`)
+
+	native, metadata, err := rw.ReadNative(context.TODO(), contentUUID, logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	defer native.Close()
+	assert.Equal(t, "tid_3", metadata.WriteReference, "the draft itself is still written in drafts/content")
+
+	versions, err := history.Versions(context.TODO(), contentUUID, logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, "tid_3", versions[0].WriteReference, "newest first")
+	assert.Equal(t, "tid_2", versions[1].WriteReference)
+	assert.Equal(t, contentTypeArticle, versions[0].ContentType)
+
+	version, metadata, err := history.ReadVersion(context.TODO(), contentUUID, "tid_2", logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	defer version.Close()
+	body, err := io.ReadAll(version)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title":"Second"}`, string(body))
+	assert.Equal(t, originIDcctTest, metadata.OriginSystemID)
+
+	_, _, err = history.ReadVersion(context.TODO(), contentUUID, "tid_1", logger.NewUPPLogger("test logger", "debug"))
+	assert.Equal(t, ErrDraftVersionNotFound, err, "versions beyond the limit are pruned")
+	assert.Equal(t, 4, store.count(), "the draft, its index and its two versions")
+
+	_, err = history.Versions(context.TODO(), "0e7ad51c-4a0b-11e7-8b62-0c6e6bd7e9a4", logger.NewUPPLogger("test logger", "debug"))
+	assert.Equal(t, ErrDraftNotFound, err)
+}
+
+func TestGenericRWWithoutHistory(t *testing.T) {
+	_, ok := NewDraftContentHistoryRWService("http://localhost", nil, http.DefaultClient, 0).(DraftContentHistory)
+	assert.False(t, ok)
+}
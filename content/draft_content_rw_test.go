@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Financial-Times/go-ft-http/fthttp"
@@ -56,7 +57,7 @@ func TestReadContent(t *testing.T) {
 	assert.NoError(t, err)
 	rw := NewDraftContentRWService(rwServer.URL, resolver, testClient)
 
-	body, err := rw.Read(ctx, contentUUID, testLogger)
+	body, _, err := rw.Read(ctx, contentUUID, testLogger)
 	assert.NoError(t, err)
 	defer body.Close()
 	actual, err := io.ReadAll(body)
@@ -82,7 +83,7 @@ func TestReadContentNotFound(t *testing.T) {
 	assert.NoError(t, err)
 	rw := NewDraftContentRWService(rwServer.URL, resolver, testClient)
 
-	body, err := rw.Read(ctx, contentUUID, testLogger)
+	body, _, err := rw.Read(ctx, contentUUID, testLogger)
 	assert.Error(t, err, ErrDraftNotFound.Error())
 	assert.Nil(t, body, "mapped content")
 	validator.mock.AssertExpectations(t)
@@ -104,7 +105,7 @@ func TestReadContentError(t *testing.T) {
 	assert.NoError(t, err)
 	rw := NewDraftContentRWService(rwServer.URL, resolver, testClient)
 
-	body, err := rw.Read(ctx, contentUUID, testLogger)
+	body, _, err := rw.Read(ctx, contentUUID, testLogger)
 	assert.Error(t, err, "service unavailable", "r/w error")
 	assert.Nil(t, body, "mapped content")
 	validator.mock.AssertExpectations(t)
@@ -128,7 +129,7 @@ func TestReadContentValidatorError(t *testing.T) {
 	assert.NoError(t, err)
 	rw := NewDraftContentRWService(rwServer.URL, resolver, testClient)
 
-	body, err := rw.Read(ctx, contentUUID, testLogger)
+	body, _, err := rw.Read(ctx, contentUUID, testLogger)
 	assert.Error(t, err, "test validator error")
 	assert.Nil(t, body, "mapped content")
 	validator.mock.AssertExpectations(t)
@@ -152,12 +153,58 @@ func TestReadContentValidatorUnprocessableEntityError(t *testing.T) {
 	assert.NoError(t, err)
 	rw := NewDraftContentRWService(rwServer.URL, resolver, testClient)
 
-	body, err := rw.Read(ctx, contentUUID, testLogger)
+	body, _, err := rw.Read(ctx, contentUUID, testLogger)
 	assert.EqualError(t, err, ErrDraftNotValid.Error())
 	assert.Nil(t, body, "mapped content")
 	validator.mock.AssertExpectations(t)
 }
 
+func TestReadNativeContent(t *testing.T) {
+	contentUUID := uuid.New().String()
+	nativeContent := []byte("{\"foo\":\"bar\"}")
+	testSystemID := "foo-bar-baz"
+	ctx := tidutils.TransactionAwareContext(context.TODO(), testTID)
+	testLogger := logger.NewUPPLogger(testSystemID, "debug")
+
+	rwServer := mockReadFromGenericRW(t, http.StatusOK, contentUUID, testSystemID, nativeContent, testLastModified, testDraftRef)
+	defer rwServer.Close()
+
+	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
+	assert.NoError(t, err)
+	rw := NewDraftContentRWService(rwServer.URL, nil, testClient)
+
+	body, metadata, err := rw.ReadNative(ctx, contentUUID, testLogger)
+	assert.NoError(t, err)
+	defer body.Close()
+	actual, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.Equal(t, nativeContent, actual, "content")
+	assert.Equal(t, DraftMetadata{
+		ContentType:    contentTypeArticle,
+		OriginSystemID: testSystemID,
+		LastModified:   testLastModified,
+		WriteReference: testDraftRef,
+	}, metadata)
+}
+
+func TestReadNativeContentNotFound(t *testing.T) {
+	contentUUID := uuid.New().String()
+	testSystemID := "foo-bar-baz"
+	ctx := tidutils.TransactionAwareContext(context.TODO(), testTID)
+	testLogger := logger.NewUPPLogger(testSystemID, "debug")
+
+	rwServer := mockReadFromGenericRW(t, http.StatusNotFound, contentUUID, testSystemID, []byte("{\"message\":\"not found\"}"), "", "")
+	defer rwServer.Close()
+
+	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
+	assert.NoError(t, err)
+	rw := NewDraftContentRWService(rwServer.URL, nil, testClient)
+
+	body, _, err := rw.ReadNative(ctx, contentUUID, testLogger)
+	assert.Equal(t, ErrDraftNotFound, err)
+	assert.Nil(t, body, "native content")
+}
+
 func TestWriteContent(t *testing.T) {
 	contentUUID := uuid.New().String()
 	content := "{\"foo\":\"bar\"}"
@@ -175,7 +222,7 @@ func TestWriteContent(t *testing.T) {
 	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
 	assert.NoError(t, err)
 	rw := NewDraftContentRWService(server.URL, nil, testClient)
-	assert.NoError(t, rw.Write(context.TODO(), contentUUID, &content, headers, testLogger))
+	assert.NoError(t, rw.Write(context.TODO(), contentUUID, strings.NewReader(content), headers, testLogger))
 }
 
 func TestWriteContentWriterReturnsStatusCreated(t *testing.T) {
@@ -195,7 +242,7 @@ func TestWriteContentWriterReturnsStatusCreated(t *testing.T) {
 	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
 	assert.NoError(t, err)
 	rw := NewDraftContentRWService(server.URL, nil, testClient)
-	assert.NoError(t, rw.Write(context.TODO(), contentUUID, &content, headers, testLogger))
+	assert.NoError(t, rw.Write(context.TODO(), contentUUID, strings.NewReader(content), headers, testLogger))
 }
 
 func TestWriteContentWriterReturnsError(t *testing.T) {
@@ -215,11 +262,32 @@ func TestWriteContentWriterReturnsError(t *testing.T) {
 	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
 	assert.NoError(t, err)
 	rw := NewDraftContentRWService(server.URL, nil, testClient)
-	err = rw.Write(context.TODO(), contentUUID, &content, headers, testLogger)
+	err = rw.Write(context.TODO(), contentUUID, strings.NewReader(content), headers, testLogger)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "content RW returned an unexpected HTTP status code in write operation", "error message")
 }
 
+func TestWriteContentConditionally(t *testing.T) {
+	contentUUID := uuid.New().String()
+	testLogger := logger.NewUPPLogger("test logger", "debug")
+	headers := map[string]string{
+		tidutils.TransactionIDHeader: testTID,
+		originSystemIdHeader:         originIDcctTest,
+		contentTypeHeader:            testContentType,
+		ifMatchHeader:                "tid_draft",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `"tid_draft"`, r.Header.Get(ifMatchHeader))
+		w.WriteHeader(http.StatusPreconditionFailed)
+	}))
+	defer server.Close()
+
+	rw := NewDraftContentRWService(server.URL, nil, server.Client())
+	err := rw.Write(context.TODO(), contentUUID, strings.NewReader(`{"foo":"bar"}`), headers, testLogger)
+	assert.Equal(t, ErrDraftModified, err)
+}
+
 func mockReadFromGenericRW(t *testing.T, status int, contentUUID string, systemID string, body []byte, lastModified string, writeRef string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method, "HTTP method")
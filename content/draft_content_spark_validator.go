@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 
 	"github.com/Financial-Times/draft-content-api/platform"
 	"github.com/Financial-Times/go-logger/v2"
 	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type sparkDraftContentValidator struct {
@@ -27,7 +29,14 @@ func (validator *sparkDraftContentValidator) Validate(
 	nativeBody io.Reader,
 	contentType string,
 	log *logger.UPPLogger,
-) (io.ReadCloser, error) {
+) (_ io.ReadCloser, err error) {
+	ctx, span := startSpan(ctx, "sparkDraftContentValidator.Validate",
+		attribute.String("uuid", contentUUID),
+		attribute.String("content_type", contentType),
+		attribute.String("endpoint", validator.service.Endpoint()),
+	)
+	defer func() { endSpan(span, err) }()
+
 	tid, _ := tidutils.GetTransactionIDFromContext(ctx)
 	mapLog := log.WithField(tidutils.TransactionIDHeader, tid).WithField("uuid", contentUUID)
 
@@ -65,24 +74,13 @@ func (validator *sparkDraftContentValidator) Validate(
 			}
 		}
 
-		responseBody := make(map[string]interface{})
-		err = json.Unmarshal(responseBytes, &responseBody)
-
-		if err != nil {
-			return nil, ValidatorError{resp.StatusCode,
-				fmt.Sprintf(
-					"Validation has failed for uuid: %s but couldn't unmarshal response body, error: %v",
-					contentUUID,
-					err,
-				),
-			}
-		}
-
 		errorMessage := fmt.Sprintf(
-			"Content with uuid: %s, content-type: %s has failed validation/mapping with reason: %v",
+			"Content with uuid: %s, content-type: %s has failed validation/mapping with status %d, error code: %s (%d bytes)",
 			contentUUID,
 			contentType,
-			responseBody["error"],
+			resp.StatusCode,
+			validationErrorCode(responseBytes),
+			len(responseBytes),
 		)
 
 		return nil, ValidatorError{resp.StatusCode, errorMessage}
@@ -102,6 +100,37 @@ func (validator *sparkDraftContentValidator) GTG() error {
 	return validator.service.GTG()
 }
 
+func (validator *sparkDraftContentValidator) GTGContext(ctx context.Context) error {
+	return validator.service.GTGContext(ctx)
+}
+
+// BulkheadStats reports the usage of the concurrency limit of the validator, if its client has one.
+func (validator *sparkDraftContentValidator) BulkheadStats() (platform.BulkheadStats, bool) {
+	return validator.service.BulkheadStats()
+}
+
 func (validator *sparkDraftContentValidator) Endpoint() string {
 	return validator.service.Endpoint()
 }
+
+var errorCodePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// validationErrorCode reduces the response of a failed validation to one of a few codes, or the error of the response
+// when it is an identifier, since validation errors may quote the draft and must not reach the logs.
+func validationErrorCode(response []byte) string {
+	var body map[string]interface{}
+	if err := json.Unmarshal(response, &body); err != nil {
+		return "unparseable"
+	}
+	switch reason := body["error"].(type) {
+	case nil:
+		return "none"
+	case string:
+		if errorCodePattern.MatchString(reason) {
+			return reason
+		}
+		return "message"
+	default:
+		return "structured"
+	}
+}
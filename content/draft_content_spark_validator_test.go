@@ -117,3 +117,16 @@ func mockSparkValidatorHTTPServer(t *testing.T, status int, expectedBody string,
 		assert.NoError(t, err)
 	}))
 }
+
+func TestValidationErrorCodeLeavesOutTheDraft(t *testing.T) {
+	tests := map[string]string{
+		`{"error":"MISSING_TITLE"}`:                    "MISSING_TITLE",
+		`{"error":"Invalid title 'Embargoed merger'"}`: "message",
+		`{"error":{"draft":{"bodyXML":"<body/>"}}}`:    "structured",
+		`{"message":"Invalid title"}`:                  "none",
+		`<html><body>Embargoed merger</body></html>`:   "unparseable",
+	}
+	for response, code := range tests {
+		assert.Equal(t, code, validationErrorCode([]byte(response)), response)
+	}
+}
@@ -2,6 +2,8 @@ package content
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // DraftContentValidatorResolver manages the validators available for a given originId/content-type pair.
@@ -26,11 +28,21 @@ func (resolver *draftContentValidatorResolver) ValidatorForContentType(contentTy
 
 	if !found {
 		return nil, fmt.Errorf(
-			"no validator configured for contentType: %s\ncontentTypeMap: %v",
+			"no validator configured for contentType: %s, configured content types: %s",
 			contentType,
-			resolver.contentTypeToValidator,
+			strings.Join(resolver.contentTypes(), ", "),
 		)
 	}
 
 	return validator, nil
 }
+
+// contentTypes lists the configured content types, without their validators, whose endpoints and clients are not meant for the logs.
+func (resolver *draftContentValidatorResolver) contentTypes() []string {
+	contentTypes := make([]string, 0, len(resolver.contentTypeToValidator))
+	for contentType := range resolver.contentTypeToValidator {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+	return contentTypes
+}
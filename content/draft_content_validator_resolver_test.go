@@ -26,6 +26,15 @@ func TestDraftContentValidatorResolver_MissingSparkValidation(t *testing.T) {
 	assert.Nil(t, validator)
 }
 
+func TestDraftContentValidatorResolver_ErrorListsContentTypesOnly(t *testing.T) {
+	ucv := NewSparkDraftContentValidatorService("http://upp-article-validator:8080", http.DefaultClient)
+	resolver := NewDraftContentValidatorResolver(cctOnlyResolverConfig(ucv))
+
+	_, err := resolver.ValidatorForContentType("application/vnd.ft-upp-audio+json")
+
+	assert.EqualError(t, err, "no validator configured for contentType: application/vnd.ft-upp-audio+json, configured content types: "+contentTypeArticle)
+}
+
 func cctOnlyResolverConfig(ucv DraftContentValidator) (contentTypeToValidator map[string]DraftContentValidator) {
 	return map[string]DraftContentValidator{
 		contentTypeArticle: ucv,
@@ -0,0 +1,56 @@
+package content
+
+import (
+	"context"
+	"sync"
+)
+
+// draftLocks serialises read-modify-write operations on the same draft within this instance of the service.
+// Writes made by the other instances are caught by the conditional write to the draft store.
+type draftLocks struct {
+	mu    sync.Mutex
+	locks map[string]*draftLock
+}
+
+type draftLock struct {
+	held    chan struct{}
+	holders int
+}
+
+func newDraftLocks() *draftLocks {
+	return &draftLocks{locks: map[string]*draftLock{}}
+}
+
+// lock waits until the draft is free, or until ctx is done, and returns the function releasing it.
+func (l *draftLocks) lock(ctx context.Context, contentUUID string) (func(), error) {
+	l.mu.Lock()
+	dl, found := l.locks[contentUUID]
+	if !found {
+		dl = &draftLock{held: make(chan struct{}, 1)}
+		l.locks[contentUUID] = dl
+	}
+	dl.holders++
+	l.mu.Unlock()
+
+	select {
+	case dl.held <- struct{}{}:
+	case <-ctx.Done():
+		l.release(contentUUID, dl)
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		<-dl.held
+		l.release(contentUUID, dl)
+	}, nil
+}
+
+func (l *draftLocks) release(contentUUID string, dl *draftLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	dl.holders--
+	if dl.holders == 0 {
+		delete(l.locks, contentUUID)
+	}
+}
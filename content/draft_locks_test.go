@@ -0,0 +1,32 @@
+package content
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraftLocksWaitWithTheContext(t *testing.T) {
+	locks := newDraftLocks()
+
+	unlock, err := locks.lock(context.Background(), "draft")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = locks.lock(ctx, "draft")
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	other, err := locks.lock(context.Background(), "other draft")
+	require.NoError(t, err, "other drafts are not locked")
+	other()
+
+	unlock()
+	unlock, err = locks.lock(context.Background(), "draft")
+	require.NoError(t, err)
+	unlock()
+	assert.Empty(t, locks.locks)
+}
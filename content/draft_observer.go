@@ -0,0 +1,78 @@
+package content
+
+import (
+	"context"
+	"time"
+
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+)
+
+// DraftEvent describes a draft that has been stored.
+type DraftEvent struct {
+	UUID           string    `json:"uuid"`
+	ContentType    string    `json:"contentType"`
+	OriginSystemID string    `json:"originSystemId"`
+	TransactionID  string    `json:"transactionId"`
+	WriteReference string    `json:"writeReference"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// DraftObserver is notified by the Handler after a draft has been successfully written.
+// Implementations must not block, the request context is cancelled as soon as the response is sent.
+type DraftObserver interface {
+	DraftChanged(ctx context.Context, event DraftEvent)
+}
+
+// DraftValidationObserver is notified by the Handler when a stored draft fails validation on read.
+// Implementations must not block, as for DraftObserver.
+type DraftValidationObserver interface {
+	DraftValidationFailed(ctx context.Context, event DraftEvent)
+}
+
+// HandlerOption configures optional Handler behaviour.
+type HandlerOption func(*Handler)
+
+// WithDraftObserver registers an observer of draft writes.
+func WithDraftObserver(observer DraftObserver) HandlerOption {
+	return func(h *Handler) {
+		h.observers = append(h.observers, observer)
+	}
+}
+
+// WithDraftValidationObserver registers an observer of drafts failing validation.
+func WithDraftValidationObserver(observer DraftValidationObserver) HandlerOption {
+	return func(h *Handler) {
+		h.validationObservers = append(h.validationObservers, observer)
+	}
+}
+
+func (h *Handler) notifyDraftChanged(ctx context.Context, contentUUID string, headers map[string]string) {
+	event := DraftEvent{
+		UUID:           contentUUID,
+		ContentType:    headers[contentTypeHeader],
+		OriginSystemID: headers[originSystemIdHeader],
+		TransactionID:  headers[tidutils.TransactionIDHeader],
+		WriteReference: headers[tidutils.TransactionIDHeader],
+		Timestamp:      time.Now().UTC(),
+	}
+
+	for _, observer := range h.observers {
+		observer.DraftChanged(ctx, event)
+	}
+}
+
+func (h *Handler) notifyDraftValidationFailed(ctx context.Context, contentUUID string, metadata DraftMetadata) {
+	tid, _ := tidutils.GetTransactionIDFromContext(ctx)
+	event := DraftEvent{
+		UUID:           contentUUID,
+		ContentType:    metadata.ContentType,
+		OriginSystemID: metadata.OriginSystemID,
+		TransactionID:  tid,
+		WriteReference: metadata.WriteReference,
+		Timestamp:      time.Now().UTC(),
+	}
+
+	for _, observer := range h.validationObservers {
+		observer.DraftValidationFailed(ctx, event)
+	}
+}
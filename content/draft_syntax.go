@@ -0,0 +1,65 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// DraftSyntaxError describes why a draft is not a well-formed JSON object.
+// Offset is the position in bytes of the error in the draft as it was received.
+type DraftSyntaxError struct {
+	Offset int64
+	msg    string
+}
+
+func (e *DraftSyntaxError) Error() string {
+	return fmt.Sprintf("%v at byte offset %d", e.msg, e.Offset)
+}
+
+// normaliseDraft checks that a draft is a well-formed JSON object encoded in UTF-8, so that malformed drafts
+// are rejected on write rather than failing every later read. A leading byte order mark is stripped.
+func normaliseDraft(draft []byte) ([]byte, error) {
+	offset := int64(0)
+	if bytes.HasPrefix(draft, utf8BOM) {
+		draft = draft[len(utf8BOM):]
+		offset = int64(len(utf8BOM))
+	}
+
+	if invalid := invalidUTF8Offset(draft); invalid >= 0 {
+		return nil, &DraftSyntaxError{Offset: offset + invalid, msg: "invalid UTF-8 encoding"}
+	}
+
+	var raw json.RawMessage
+	if err := json.Unmarshal(draft, &raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, &DraftSyntaxError{Offset: offset + syntaxErr.Offset, msg: syntaxErr.Error()}
+		}
+		return nil, err
+	}
+
+	if value := bytes.TrimLeft(draft, " \t\r\n"); value[0] != '{' {
+		return nil, &DraftSyntaxError{Offset: offset + int64(len(draft)-len(value)), msg: "draft content is not a JSON object"}
+	}
+	return draft, nil
+}
+
+// invalidUTF8Offset returns the offset of the first invalid UTF-8 sequence of b, -1 when b is valid.
+func invalidUTF8Offset(b []byte) int64 {
+	if utf8.Valid(b) {
+		return -1
+	}
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size == 1 {
+			return int64(i)
+		}
+		i += size
+	}
+	return -1
+}
@@ -0,0 +1,106 @@
+package content
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormaliseDraft(t *testing.T) {
+	draft, err := normaliseDraft([]byte("\xef\xbb\xbf{\"title\":\"Café\"}"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"title":"Café"}`, string(draft), "the byte order mark is stripped")
+
+	draft, err = normaliseDraft([]byte(" {\"title\":\"Draft\"}\n"))
+	require.NoError(t, err)
+	assert.Equal(t, " {\"title\":\"Draft\"}\n", string(draft))
+}
+
+func TestNormaliseDraftInvalid(t *testing.T) {
+	tests := map[string]struct {
+		draft  string
+		offset int64
+	}{
+		"syntax error":             {draft: `{"title": "Draft",}`, offset: 19},
+		"syntax error after a BOM": {draft: "\xef\xbb\xbf{\"title\" \"Draft\"}", offset: 13},
+		"truncated draft":          {draft: `{"title": "Dra`, offset: 14},
+		"empty draft":              {draft: ``, offset: 0},
+		"trailing data":            {draft: `{"title": "Draft"} {}`, offset: 20},
+		"invalid UTF-8":            {draft: "{\"title\": \"Caf\xe9\"}", offset: 14},
+		"not an object":            {draft: ` ["Draft"]`, offset: 1},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := normaliseDraft([]byte(test.draft))
+			var syntaxErr *DraftSyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, test.offset, syntaxErr.Offset)
+		})
+	}
+}
+
+func TestWriteNativeContentMalformedDraft(t *testing.T) {
+	contentUUID := uuid.New().String()
+
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+	AllowedContentTypes = map[string]struct{}{
+		contentTypeArticle: {},
+	}
+
+	rw := mockDraftContentRW{}
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+
+	req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(`{"title": "Draft",}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, contentTypeArticle)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "at byte offset 19")
+	rw.mock.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWriteNativeContentStripsByteOrderMark(t *testing.T) {
+	contentUUID := uuid.New().String()
+	draftBody := `{"title":"Draft"}`
+
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+	AllowedContentTypes = map[string]struct{}{
+		contentTypeArticle: {},
+	}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(nil)
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+
+	req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader("\xef\xbb\xbf"+draftBody))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, contentTypeArticle)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	rw.mock.AssertExpectations(t)
+}
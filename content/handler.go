@@ -1,6 +1,7 @@
 package content
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,10 +12,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Financial-Times/draft-content-api/platform"
 	"github.com/Financial-Times/go-logger/v2"
 	tidutils "github.com/Financial-Times/transactionid-utils-go"
 	"github.com/google/uuid"
 	"github.com/husobee/vestigo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -23,6 +30,15 @@ const (
 
 	contentTypeHeader    = "Content-Type"
 	originSystemIdHeader = "X-Origin-System-Id"
+	contentSourceHeader  = "X-Content-Source"
+	draftReferenceHeader = "X-Draft-Reference"
+	ifMatchHeader        = "If-Match"
+
+	mergePatchContentType = "application/merge-patch+json"
+
+	contentSourceDraft     = "draft"
+	contentSourcePublished = "published"
+	contentSourceAny       = "any"
 )
 
 var (
@@ -30,43 +46,109 @@ var (
 	AllowedContentTypes         = map[string]struct{}{}
 )
 
-type contentProviderAPI interface {
+// ContentProviderAPI provides the published version of content, used when there is no draft.
+type ContentProviderAPI interface {
 	Get(ctx context.Context, contentUUID string, log *logger.UPPLogger) (*http.Response, error)
 	GTG() error
 	Endpoint() string
 }
 
 type Handler struct {
-	uppContentAPI contentProviderAPI
-	contentRW     DraftContentRW
-	timeout       time.Duration
-	log           *logger.UPPLogger
+	uppContentAPI       ContentProviderAPI
+	contentRW           DraftContentRW
+	history             DraftContentHistory
+	locks               *draftLocks
+	observers           []DraftObserver
+	validationObservers []DraftValidationObserver
+	writeLimiter        *WriteLimiter
+	adaptiveLimiter     *AdaptiveLimiter
+	defaultMaxBodySize  int64
+	maxBodySizes        map[string]int64
+	uuidPointers        map[string]string
+	idempotency         IdempotencyStore
+	auditor             DraftAuditor
+	timeout             time.Duration
+	log                 *logger.UPPLogger
 }
 
-func NewHandler(uppAPI contentProviderAPI, draftContentRW DraftContentRW, timeout time.Duration, log *logger.UPPLogger) *Handler {
-	return &Handler{uppAPI, draftContentRW, timeout, log}
+func NewHandler(uppAPI ContentProviderAPI, draftContentRW DraftContentRW, timeout time.Duration, log *logger.UPPLogger, opts ...HandlerOption) *Handler {
+	history, _ := draftContentRW.(DraftContentHistory)
+	h := &Handler{
+		uppContentAPI: uppAPI,
+		contentRW:     draftContentRW,
+		history:       history,
+		locks:         newDraftLocks(),
+		timeout:       timeout,
+		log:           log,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
+// ReadContent returns the mapped draft, falling back to the published UPP version when no draft exists.
+// The source query parameter forces one of the two paths; the X-Content-Source response header reports which one was used.
 func (h *Handler) ReadContent(w http.ResponseWriter, r *http.Request) {
 
-	contentId := vestigo.Param(r, "uuid")
+	contentId := contentUUIDParam(r)
+
+	w, audit := h.startAudit(w, r, AuditActionRead, contentId)
+	defer audit.end(r.Context())
+
+	source, err := validateContentSource(r.URL.Query().Get("source"))
+	if err != nil {
+		writeMessage(w, fmt.Sprintf("Invalid source: %v", source), http.StatusBadRequest)
+		return
+	}
+
+	readLog := h.log.WithField(tidutils.TransactionIDHeader, tidutils.GetTransactionIDFromRequest(r)).WithField("uuid", contentId)
+	release, admitted := h.admitRequest(w, readLog, PriorityRead)
+	if !admitted {
+		return
+	}
+	defer release()
 
 	ctx, cancelCtx := context.WithTimeout(newContextFromRequest(r), h.timeout)
 	defer cancelCtx()
 
-	content, err := h.contentRW.Read(ctx, contentId, h.log)
+	ctx, span := startSpan(ctx, "Handler.ReadContent", attribute.String("uuid", contentId), attribute.String("source", source))
+	defer span.End()
+
+	if source == contentSourcePublished {
+		h.readContentFromUPP(ctx, w, contentId)
+		return
+	}
+
+	content, metadata, _, err := h.readCanonicalDraft(ctx, r, contentId, h.contentRW.Read)
+	if err != ErrDraftNotFound {
+		recordSpanError(span, err)
+	}
 
 	if isTimeoutError(err) {
 		writeMessage(w, errorMessageForRead(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
 		return
 	}
 
+	if errors.Is(err, platform.ErrBulkheadFull) {
+		writeValidatorUnavailable(w)
+		return
+	}
+
 	if err == ErrDraftNotValid {
+		h.notifyDraftValidationFailed(ctx, contentId, metadata)
 		writeMessage(w, errorMessageForRead(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
 		return
 	}
 
 	if err == ErrDraftNotFound {
+		if source == contentSourceDraft {
+			writeMessage(w, errorMessageForRead(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		h.log.WithField(tidutils.TransactionIDHeader, ctx.Value(tidutils.TransactionIDHeader)).WithField("uuid", contentId).Warn("Draft not found in PAC, trying UPP")
 		h.readContentFromUPP(ctx, w, contentId)
 		return
 	}
@@ -78,14 +160,25 @@ func (h *Handler) ReadContent(w http.ResponseWriter, r *http.Request) {
 
 	defer content.Close()
 
+	audit.metadata(metadata)
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(contentSourceHeader, contentSourceDraft)
+	if metadata.WriteReference != "" {
+		w.Header().Set(draftReferenceHeader, metadata.WriteReference)
+	}
+	if metadata.LastModified != "" {
+		w.Header().Set(lastModifiedHeader, metadata.LastModified)
+	}
 	w.WriteHeader(http.StatusOK)
 	io.Copy(w, content)
 
 }
 
 func (h *Handler) WriteNativeContent(w http.ResponseWriter, r *http.Request) {
-	contentId := vestigo.Param(r, "uuid")
+	contentId := contentUUIDParam(r)
+
+	w, audit := h.startAudit(w, r, AuditActionWrite, contentId)
+	defer audit.end(r.Context())
 
 	tID := tidutils.GetTransactionIDFromRequest(r)
 
@@ -111,17 +204,72 @@ func (h *Handler) WriteNativeContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	raw, err := io.ReadAll(r.Body)
+	body, ok := h.limitBody(w, r, contentType)
+	if !ok {
+		return
+	}
+
+	// the draft is buffered, up to the body limit, since it is checked as a whole before it is written
+	raw, err := io.ReadAll(body)
 	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeBodyTooLarge(w, tooLarge.Limit)
+			return
+		}
 		writeLog.WithError(err).Error("Unable to read draft content body")
 		writeMessage(w, fmt.Sprintf("Unable to read draft content body: %v", err.Error()), http.StatusBadRequest)
 		return
 	}
 
+	draft, err := normaliseDraft(raw)
+	if err != nil {
+		writeLog.WithError(err).Warn("Invalid draft content")
+		writeMessage(w, fmt.Sprintf("Invalid draft content: %v", err.Error()), http.StatusBadRequest)
+		return
+	}
+	audit.draft(contentType, draft)
+
+	if err = h.checkDraftUUID(contentType, contentId, draft); err != nil {
+		writeLog.WithError(err).Warn("Draft content does not match its uuid")
+		writeMessage(w, fmt.Sprintf("Invalid draft content: %v", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	idempotent, ok := h.startIdempotentWrite(w, r, writeLog, contentId, draftWriteFingerprint(contentType, originSystemId, draft))
+	if !ok {
+		return
+	}
+	defer idempotent.end()
+
+	// retries replayed from the Idempotency-Key are not limited, they do not reach the draft store
+	release, admitted := h.admitWrite(w, writeLog, originSystemId, contentId)
+	if !admitted {
+		return
+	}
+	defer release()
+
 	ctx, cancelCtx := context.WithTimeout(newContextFromRequest(r), h.timeout)
 	defer cancelCtx()
 
-	draftContent := string(raw)
+	ctx, span := startSpan(ctx, "Handler.WriteNativeContent", attribute.String("uuid", contentId), attribute.String("content_type", contentType))
+	defer span.End()
+
+	unlock, err := h.locks.lock(ctx, contentId)
+	if err != nil {
+		writeLog.WithError(err).Warn("Timed out waiting for a concurrent write of the draft")
+		writeMessage(w, "Timed out waiting for a concurrent write of the draft", http.StatusGatewayTimeout)
+		return
+	}
+	defer unlock()
+
+	// admitted once the body is read and the draft lock is held, so that only the time spent on the upstreams is sampled
+	completed, admitted := h.admitRequest(w, writeLog, PriorityWrite)
+	if !admitted {
+		return
+	}
+	defer completed()
+
 	draftHeaders := map[string]string{
 		tidutils.TransactionIDHeader: tID,
 		originSystemIdHeader:         originSystemId,
@@ -129,9 +277,11 @@ func (h *Handler) WriteNativeContent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	writeLog.Info("write native content to content RW ...")
-	err = h.contentRW.Write(ctx, contentId, &draftContent, draftHeaders, h.log)
+	err = h.contentRW.Write(ctx, contentId, bytes.NewReader(draft), draftHeaders, h.log)
 	if err != nil {
 		writeLog.WithError(err).Error("Error in writing draft content")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 
 		if isTimeoutError(err) {
 			writeMessage(w, fmt.Sprintf("Error in writing draft content: %v", err.Error()), http.StatusGatewayTimeout)
@@ -143,82 +293,441 @@ func (h *Handler) WriteNativeContent(w http.ResponseWriter, r *http.Request) {
 
 	}
 
+	idempotent.succeeded(ctx, writeLog, http.StatusOK)
+	audit.metadata(DraftMetadata{ContentType: contentType, OriginSystemID: originSystemId, WriteReference: tID})
+	h.notifyDraftChanged(ctx, contentId, draftHeaders)
+
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *Handler) readContentFromUPP(ctx context.Context, w http.ResponseWriter, contentId string) {
-	readContentUPPLog := h.log.WithField(tidutils.TransactionIDHeader, ctx.Value(tidutils.TransactionIDHeader)).WithField("uuid", contentId)
-	readContentUPPLog.Warn("Draft not found in PAC, trying UPP")
-	uppResp, err := h.uppContentAPI.Get(ctx, contentId, h.log)
+// ReadNativeContent returns the stored draft exactly as it was written, with its original headers.
+func (h *Handler) ReadNativeContent(w http.ResponseWriter, r *http.Request) {
+	contentId := contentUUIDParam(r)
+
+	w, audit := h.startAudit(w, r, AuditActionReadNative, contentId)
+	defer audit.end(r.Context())
+
+	ctx, cancelCtx := context.WithTimeout(newContextFromRequest(r), h.timeout)
+	defer cancelCtx()
+
+	native, metadata, _, err := h.readCanonicalDraft(ctx, r, contentId, h.contentRW.ReadNative)
+	switch {
+	case err == nil:
+	case err == ErrDraftNotFound:
+		writeMessage(w, errorMessageForRead(http.StatusNotFound), http.StatusNotFound)
+		return
+	case isTimeoutError(err):
+		writeMessage(w, errorMessageForRead(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+		return
+	default:
+		writeMessage(w, errorMessageForRead(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer native.Close()
+
+	audit.metadata(metadata)
+	writeNativeDraft(w, native, metadata)
+}
+
+// PatchNativeContent applies a JSON Merge Patch to the stored native draft.
+// The If-Match header must carry the reference of the draft the patch was made against,
+// so that concurrent changes are rejected rather than silently overwritten: the patched draft is written back
+// conditionally on that reference, and writes of the draft within this instance are serialised.
+func (h *Handler) PatchNativeContent(w http.ResponseWriter, r *http.Request) {
+	contentId := contentUUIDParam(r)
+
+	w, audit := h.startAudit(w, r, AuditActionPatch, contentId)
+	defer audit.end(r.Context())
+
+	tID := tidutils.GetTransactionIDFromRequest(r)
+
+	patchLog := h.log.WithField(tidutils.TransactionIDHeader, tID).WithField("uuid", contentId)
+
+	if err := validateUUID(contentId); err != nil {
+		patchLog.WithError(err).Error("Invalid content UUID")
+		writeMessage(w, fmt.Sprintf("Invalid content UUID: %v", contentId), http.StatusBadRequest)
+		return
+	}
 
+	originSystemId, err := validateOrigin(r.Header.Get(originSystemIdHeader))
 	if err != nil {
-		readContentUPPLog.WithError(err).Error("Error in calling Content API")
+		patchLog.WithError(err).Error("Invalid origin system id")
+		writeMessage(w, fmt.Sprintf("Invalid origin system id: %v", originSystemId), http.StatusBadRequest)
+		return
+	}
+
+	release, admitted := h.admitWrite(w, patchLog, originSystemId, contentId)
+	if !admitted {
+		return
+	}
+	defer release()
+
+	if contentType := r.Header.Get(contentTypeHeader); stripMediaTypeParameters(contentType) != mergePatchContentType {
+		writeMessage(w, fmt.Sprintf("Invalid content type: %v", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	draftRef := strings.Trim(r.Header.Get(ifMatchHeader), `"`)
+	if draftRef == "" {
+		writeMessage(w, "Missing If-Match header with the draft reference", http.StatusPreconditionRequired)
+		return
+	}
+
+	patchBody, ok := h.limitBody(w, r, "")
+	if !ok {
+		return
+	}
+
+	var patch interface{}
+	dec := json.NewDecoder(patchBody)
+	dec.UseNumber()
+	if err = dec.Decode(&patch); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeBodyTooLarge(w, tooLarge.Limit)
+			return
+		}
+		patchLog.WithError(err).Error("Unable to read draft content patch")
+		writeMessage(w, fmt.Sprintf("Unable to read draft content patch: %v", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if _, isObject := patch.(map[string]interface{}); !isObject {
+		writeMessage(w, "Draft content patch must be a JSON object", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancelCtx := context.WithTimeout(newContextFromRequest(r), h.timeout)
+	defer cancelCtx()
+
+	unlock, err := h.locks.lock(ctx, contentId)
+	if err != nil {
+		patchLog.WithError(err).Warn("Timed out waiting for a concurrent write of the draft")
+		writeMessage(w, "Timed out waiting for a concurrent write of the draft", http.StatusGatewayTimeout)
+		return
+	}
+	defer unlock()
+
+	// admitted once the body is read and the draft lock is held, so that only the time spent on the upstreams is sampled
+	completed, admitted := h.admitRequest(w, patchLog, PriorityWrite)
+	if !admitted {
+		return
+	}
+	defer completed()
+
+	// a draft still stored under a non canonical uuid is patched where it is, so that the If-Match reference holds
+	native, metadata, storedId, err := h.readCanonicalDraft(ctx, r, contentId, h.contentRW.ReadNative)
+	switch {
+	case err == nil:
+	case err == ErrDraftNotFound:
+		writeMessage(w, errorMessageForRead(http.StatusNotFound), http.StatusNotFound)
+		return
+	case isTimeoutError(err):
+		writeMessage(w, errorMessageForRead(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+		return
+	default:
+		patchLog.WithError(err).Error("Error in reading native draft content")
+		writeMessage(w, errorMessageForRead(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer native.Close()
+
+	if metadata.WriteReference != draftRef {
+		writeMessage(w, fmt.Sprintf("Draft has been modified, current draft reference is %v", metadata.WriteReference), http.StatusPreconditionFailed)
+		return
+	}
+
+	var document interface{}
+	dec = json.NewDecoder(native)
+	dec.UseNumber()
+	if err = dec.Decode(&document); err != nil {
+		patchLog.WithError(err).Error("Unable to unmarshal native draft content")
+		writeMessage(w, fmt.Sprintf("Unable to unmarshal native draft content: %v", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	patched, err := json.Marshal(applyMergePatch(document, patch))
+	if err != nil {
+		patchLog.WithError(err).Error("Unable to marshal patched draft content")
+		writeMessage(w, fmt.Sprintf("Unable to marshal patched draft content: %v", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	if limit := h.maxBodySize(metadata.ContentType); limit > 0 && int64(len(patched)) > limit {
+		writeBodyTooLarge(w, limit)
+		return
+	}
+
+	audit.draft(metadata.ContentType, patched)
+
+	if err = h.checkDraftUUID(metadata.ContentType, contentId, patched); err != nil {
+		patchLog.WithError(err).Warn("Patched draft content does not match its uuid")
+		writeMessage(w, fmt.Sprintf("Invalid draft content patch: %v", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	draftHeaders := map[string]string{
+		tidutils.TransactionIDHeader: tID,
+		originSystemIdHeader:         originSystemId,
+		contentTypeHeader:            metadata.ContentType,
+		ifMatchHeader:                draftRef,
+	}
+
+	patchLog.Info("write patched native content to content RW ...")
+	err = h.contentRW.Write(ctx, storedId, bytes.NewReader(patched), draftHeaders, h.log)
+	if err == ErrDraftModified {
+		patchLog.Warn("Draft modified concurrently, the patch is not applied")
+		writeMessage(w, "Draft has been modified since it was read, the patch is not applied", http.StatusPreconditionFailed)
+		return
+	}
+	if err != nil {
+		patchLog.WithError(err).Error("Error in writing draft content")
 
 		if isTimeoutError(err) {
-			writeMessage(w, err.Error(), http.StatusGatewayTimeout)
+			writeMessage(w, fmt.Sprintf("Error in writing draft content: %v", err.Error()), http.StatusGatewayTimeout)
 			return
 		}
 
-		writeMessage(w, err.Error(), http.StatusInternalServerError)
+		writeMessage(w, fmt.Sprintf("Error in writing draft content: %v", err.Error()), http.StatusInternalServerError)
 		return
 	}
 
-	defer uppResp.Body.Close()
+	audit.metadata(DraftMetadata{ContentType: metadata.ContentType, OriginSystemID: originSystemId, WriteReference: tID})
+	h.notifyDraftChanged(ctx, contentId, draftHeaders)
 
-	if uppResp.StatusCode == http.StatusGatewayTimeout {
-		writeMessage(w, errorMessageForRead(uppResp.StatusCode), http.StatusInternalServerError)
+	w.Header().Set(contentTypeHeader, metadata.ContentType)
+	w.Header().Set(writeRequestIDHeader, tID)
+	w.WriteHeader(http.StatusOK)
+	w.Write(patched)
+}
+
+// DiffContent returns the changes that publishing the current draft would make to the published UPP version.
+func (h *Handler) DiffContent(w http.ResponseWriter, r *http.Request) {
+	contentId := contentUUIDParam(r)
+
+	ctx, cancelCtx := context.WithTimeout(newContextFromRequest(r), h.timeout)
+	defer cancelCtx()
+
+	diffLog := h.log.WithField(tidutils.TransactionIDHeader, ctx.Value(tidutils.TransactionIDHeader)).WithField("uuid", contentId)
+
+	if err := validateUUID(contentId); err != nil {
+		diffLog.WithError(err).Error("Invalid content UUID")
+		writeMessage(w, fmt.Sprintf("Invalid content UUID: %v", contentId), http.StatusBadRequest)
 		return
 	}
 
-	if uppResp.StatusCode != http.StatusOK {
-		writeMessage(w, errorMessageForRead(uppResp.StatusCode), uppResp.StatusCode)
+	content, metadata, _, err := h.readCanonicalDraft(ctx, r, contentId, h.contentRW.Read)
+	switch {
+	case err == nil:
+	case isTimeoutError(err):
+		writeMessage(w, errorMessageForRead(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+		return
+	case errors.Is(err, platform.ErrBulkheadFull):
+		writeValidatorUnavailable(w)
+		return
+	case err == ErrDraftNotFound:
+		writeMessage(w, errorMessageForRead(http.StatusNotFound), http.StatusNotFound)
+		return
+	case err == ErrDraftNotValid:
+		h.notifyDraftValidationFailed(ctx, contentId, metadata)
+		writeMessage(w, errorMessageForRead(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		return
+	default:
+		writeMessage(w, errorMessageForRead(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
+	defer content.Close()
 
-	bytes, err := io.ReadAll(uppResp.Body)
+	var draft map[string]interface{}
+	if err = json.NewDecoder(content).Decode(&draft); err != nil {
+		diffLog.WithError(err).Error("Failed unmarshalling mapped draft")
+		writeMessage(w, errorMessageForRead(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
 
+	published, status, err := h.readUPPContent(ctx, contentId)
+	if status == http.StatusNotFound {
+		writeMessage(w, "Published content not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		readContentUPPLog.WithError(err).Error("Failed reading UPP response")
-		writeMessage(w, err.Error(), http.StatusInternalServerError)
+		writeMessage(w, err.Error(), status)
 		return
 	}
 
-	var uppContent map[string]interface{}
+	patch := diffJSON(withoutDiffIgnoredFields(published), withoutDiffIgnoredFields(draft))
+	diff := struct {
+		UUID    string           `json:"uuid"`
+		Patch   []patchOperation `json:"patch"`
+		Summary diffSummary      `json:"summary"`
+	}{contentId, patch, summariseDiff(patch)}
 
-	err = json.Unmarshal(bytes, &uppContent)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(diff); err != nil {
+		diffLog.WithError(err).Error("Failed writing draft diff")
+	}
+}
+
+// ListDraftVersions returns the metadata of the stored versions of a draft, newest first.
+func (h *Handler) ListDraftVersions(w http.ResponseWriter, r *http.Request) {
+	contentId := contentUUIDParam(r)
+
+	if h.history == nil {
+		writeMessage(w, "Draft versions are not supported by the draft store", http.StatusNotImplemented)
+		return
+	}
 
+	ctx, cancelCtx := context.WithTimeout(newContextFromRequest(r), h.timeout)
+	defer cancelCtx()
+
+	versions, err := h.history.Versions(ctx, contentId, h.log)
+	if legacyId, found := legacyUUIDParam(r); found && err == ErrDraftNotFound {
+		versions, err = h.history.Versions(ctx, legacyId, h.log)
+	}
+	if err == ErrDraftNotFound {
+		writeMessage(w, errorMessageForRead(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		readContentUPPLog.WithError(err).Error("Failed unmarshalling UPP response")
-		writeMessage(w, err.Error(), http.StatusInternalServerError)
+		h.log.WithField(tidutils.TransactionIDHeader, ctx.Value(tidutils.TransactionIDHeader)).WithField("uuid", contentId).
+			WithError(err).Error("Error in listing draft versions")
+		writeMessage(w, errorMessageForRead(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
-	err = h.transformUPPContent(uppContent)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(versions)
+}
+
+// ReadDraftVersion returns a single version of a draft in native format, as it was written.
+func (h *Handler) ReadDraftVersion(w http.ResponseWriter, r *http.Request) {
+	contentId := contentUUIDParam(r)
+	writeRef := vestigo.Param(r, "writeRequestId")
+
+	if h.history == nil {
+		writeMessage(w, "Draft versions are not supported by the draft store", http.StatusNotImplemented)
+		return
+	}
+
+	ctx, cancelCtx := context.WithTimeout(newContextFromRequest(r), h.timeout)
+	defer cancelCtx()
 
+	content, metadata, err := h.history.ReadVersion(ctx, contentId, writeRef, h.log)
+	if legacyId, found := legacyUUIDParam(r); found && err == ErrDraftVersionNotFound {
+		content, metadata, err = h.history.ReadVersion(ctx, legacyId, writeRef, h.log)
+	}
+	if err == ErrDraftVersionNotFound {
+		writeMessage(w, "Draft version not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		readContentUPPLog.WithError(err).Error("Failed transforming UPP response")
-		writeMessage(w, err.Error(), http.StatusInternalServerError)
+		h.log.WithField(tidutils.TransactionIDHeader, ctx.Value(tidutils.TransactionIDHeader)).WithField("uuid", contentId).
+			WithError(err).Error("Error in reading draft version")
+		writeMessage(w, errorMessageForRead(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer content.Close()
+
+	writeNativeDraft(w, content, metadata)
+}
+
+func (h *Handler) readContentFromUPP(ctx context.Context, w http.ResponseWriter, contentId string) {
+	readContentUPPLog := h.log.WithField(tidutils.TransactionIDHeader, ctx.Value(tidutils.TransactionIDHeader)).WithField("uuid", contentId)
+
+	span := trace.SpanFromContext(ctx)
+	uppContent, status, err := h.readUPPContent(ctx, contentId)
+	if err != nil {
+		if status != http.StatusNotFound {
+			recordSpanError(span, err)
+		}
+		writeMessage(w, err.Error(), status)
 		return
 	}
 
 	content, err := json.Marshal(uppContent)
 
 	if err != nil {
+		recordSpanError(span, err)
 		readContentUPPLog.WithError(err).Error("Failed marshalling transformed UPP response")
 		writeMessage(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(contentSourceHeader, contentSourcePublished)
 	w.WriteHeader(http.StatusOK)
 	w.Write(content)
 }
 
+// readUPPContent returns the published content transformed into draft format,
+// or an error together with the HTTP status that should be reported for it.
+func (h *Handler) readUPPContent(ctx context.Context, contentId string) (map[string]interface{}, int, error) {
+	readContentUPPLog := h.log.WithField(tidutils.TransactionIDHeader, ctx.Value(tidutils.TransactionIDHeader)).WithField("uuid", contentId)
+	uppResp, err := h.uppContentAPI.Get(ctx, contentId, h.log)
+
+	if err != nil {
+		readContentUPPLog.WithError(err).Error("Error in calling Content API")
+
+		if isTimeoutError(err) {
+			return nil, http.StatusGatewayTimeout, err
+		}
+
+		return nil, http.StatusInternalServerError, err
+	}
+
+	defer uppResp.Body.Close()
+
+	if uppResp.StatusCode == http.StatusGatewayTimeout {
+		return nil, http.StatusInternalServerError, errors.New(errorMessageForRead(uppResp.StatusCode))
+	}
+
+	if uppResp.StatusCode != http.StatusOK {
+		return nil, uppResp.StatusCode, errors.New(errorMessageForRead(uppResp.StatusCode))
+	}
+
+	bytes, err := io.ReadAll(uppResp.Body)
+
+	if err != nil {
+		readContentUPPLog.WithError(err).Error("Failed reading UPP response")
+		return nil, http.StatusInternalServerError, err
+	}
+
+	var uppContent map[string]interface{}
+
+	err = json.Unmarshal(bytes, &uppContent)
+
+	if err != nil {
+		readContentUPPLog.WithError(err).Error("Failed unmarshalling UPP response")
+		return nil, http.StatusInternalServerError, err
+	}
+
+	err = h.transformUPPContent(uppContent)
+
+	if err != nil {
+		readContentUPPLog.WithError(err).Error("Failed transforming UPP response")
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return uppContent, http.StatusOK, nil
+}
+
 func validateUUID(u string) error {
 	_, err := uuid.Parse(u)
 	return err
 }
 
+func validateContentSource(source string) (string, error) {
+	switch source {
+	case "":
+		return contentSourceAny, nil
+	case contentSourceDraft, contentSourcePublished, contentSourceAny:
+		return source, nil
+	}
+
+	return source, fmt.Errorf("unsupported value for source: %v", source)
+}
+
 func validateOrigin(id string) (string, error) {
 	var err error
 	if _, found := AllowedOriginSystemIDValues[id]; !found {
@@ -256,11 +765,29 @@ func errorMessageForRead(status int) string {
 
 	case http.StatusGatewayTimeout:
 		return "Draft content request processing has timed out"
+
+	case http.StatusServiceUnavailable:
+		return "Draft validator is at capacity, please retry"
 	}
 
 	return "Error reading draft content"
 }
 
+// writeValidatorUnavailable answers a read rejected by the concurrency limit of the validator of the draft.
+func writeValidatorUnavailable(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", "1")
+	writeMessage(w, errorMessageForRead(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}
+
+func writeNativeDraft(w http.ResponseWriter, content io.Reader, metadata DraftMetadata) {
+	w.Header().Set(contentTypeHeader, metadata.ContentType)
+	w.Header().Set(originSystemIdHeader, metadata.OriginSystemID)
+	w.Header().Set(lastModifiedHeader, metadata.LastModified)
+	w.Header().Set(writeRequestIDHeader, metadata.WriteReference)
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, content)
+}
+
 func writeMessage(w http.ResponseWriter, errMsg string, status int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -355,8 +882,10 @@ func (h *Handler) transformUPPContent(content map[string]interface{}) error {
 
 }
 
+// newContextFromRequest carries the transaction ID and the trace of the request, if any, to the upstream calls.
 func newContextFromRequest(request *http.Request) context.Context {
-	return tidutils.TransactionAwareContext(context.Background(), tidutils.GetTransactionIDFromRequest(request))
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(request.Header))
+	return tidutils.TransactionAwareContext(ctx, tidutils.GetTransactionIDFromRequest(request))
 }
 
 func isTimeoutError(err error) bool {
@@ -8,10 +8,12 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/Financial-Times/draft-content-api/platform"
 	"github.com/Financial-Times/go-ft-http/fthttp"
 	"github.com/Financial-Times/go-logger/v2"
 	tidutils "github.com/Financial-Times/transactionid-utils-go"
@@ -38,7 +40,7 @@ func TestHappyRead(t *testing.T) {
 	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
 
 	rw := &mockDraftContentRW{}
-	rw.mock.On("Read", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(fromUppContent)), nil)
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(fromUppContent)), DraftMetadata{}, nil)
 
 	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
 	r := vestigo.NewRouter()
@@ -67,7 +69,7 @@ func TestReadBackOffWhenNoDraftFoundToContentAPI(t *testing.T) {
 	mainImageUUID := "fba9884e-0756-11e8-0074-38e932af9738"
 
 	rw := &mockDraftContentRW{}
-	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, ErrDraftNotFound)
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, ErrDraftNotFound)
 
 	cAPIServerMock := newContentAPIServerMock(t, http.StatusOK, fromUppContent)
 	defer cAPIServerMock.Close()
@@ -117,7 +119,7 @@ func TestReadNoBackOffForOtherErrors(t *testing.T) {
 	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
 
 	rw := &mockDraftContentRW{}
-	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, errors.New("this should never happen"))
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, errors.New("this should never happen"))
 
 	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
 	r := vestigo.NewRouter()
@@ -146,7 +148,7 @@ func TestReadNotFoundAnywhere(t *testing.T) {
 	defer cAPIServerMock.Close()
 
 	rw := &mockDraftContentRW{}
-	rw.mock.On("Read", mock.Anything, mock.AnythingOfType("string")).Return(nil, ErrDraftNotFound)
+	rw.mock.On("Read", mock.Anything, mock.AnythingOfType("string")).Return(nil, DraftMetadata{}, ErrDraftNotFound)
 
 	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
 	assert.NoError(t, err)
@@ -175,7 +177,7 @@ func TestReadContentAPI504(t *testing.T) {
 	defer cAPIServerMock.Close()
 
 	rw := &mockDraftContentRW{}
-	rw.mock.On("Read", mock.Anything, mock.AnythingOfType("string")).Return(nil, ErrDraftNotFound)
+	rw.mock.On("Read", mock.Anything, mock.AnythingOfType("string")).Return(nil, DraftMetadata{}, ErrDraftNotFound)
 
 	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
 	assert.NoError(t, err)
@@ -200,7 +202,7 @@ func TestReadContentAPI504(t *testing.T) {
 
 func TestReadInvalidURL(t *testing.T) {
 	rw := &mockDraftContentRW{}
-	rw.mock.On("Read", mock.Anything, mock.AnythingOfType("string")).Return(nil, ErrDraftNotFound)
+	rw.mock.On("Read", mock.Anything, mock.AnythingOfType("string")).Return(nil, DraftMetadata{}, ErrDraftNotFound)
 	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
 	assert.NoError(t, err)
 	cAPI := NewContentAPI(":#", testBasicAuthUsername, testBasicAuthPassword, nil, testClient)
@@ -224,7 +226,7 @@ func TestReadInvalidURL(t *testing.T) {
 
 func TestReadConnectionError(t *testing.T) {
 	rw := &mockDraftContentRW{}
-	rw.mock.On("Read", mock.Anything, mock.AnythingOfType("string")).Return(nil, ErrDraftNotFound)
+	rw.mock.On("Read", mock.Anything, mock.AnythingOfType("string")).Return(nil, DraftMetadata{}, ErrDraftNotFound)
 	cAPIServerMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 	cAPIServerMock.Close()
 
@@ -248,6 +250,241 @@ func TestReadConnectionError(t *testing.T) {
 	rw.mock.AssertExpectations(t)
 }
 
+func TestReadDraftProvenanceHeaders(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	metadata := DraftMetadata{
+		ContentType:    contentTypeArticle,
+		OriginSystemID: originIDcctTest,
+		LastModified:   "2018-02-21T14:25:00Z",
+		WriteReference: "tid_draft",
+	}
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(fromUppContent)), metadata, nil)
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid", h.ReadContent)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s?source=draft", contentUUID), nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "draft", resp.Header.Get("X-Content-Source"))
+	assert.Equal(t, "tid_draft", resp.Header.Get("X-Draft-Reference"))
+	assert.Equal(t, "2018-02-21T14:25:00Z", resp.Header.Get("Last-Modified-RFC3339"))
+	rw.mock.AssertExpectations(t)
+}
+
+func TestReadPublishedProvenanceHeader(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, ErrDraftNotFound)
+
+	cAPIServerMock := newContentAPIServerMock(t, http.StatusOK, fromUppContent)
+	defer cAPIServerMock.Close()
+	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
+	assert.NoError(t, err)
+	cAPI := NewContentAPI(cAPIServerMock.URL, testBasicAuthUsername, testBasicAuthPassword, nil, testClient)
+
+	h := NewHandler(cAPI, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid", h.ReadContent)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s", contentUUID), nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "published", resp.Header.Get("X-Content-Source"))
+	assert.Empty(t, resp.Header.Get("X-Draft-Reference"))
+	rw.mock.AssertExpectations(t)
+}
+
+func TestReadSourceDraftDoesNotFallBack(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, ErrDraftNotFound)
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid", h.ReadContent)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s?source=draft", contentUUID), nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	body, err := io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "{\"message\": \"Draft not found\"}", string(body))
+	rw.mock.AssertExpectations(t)
+}
+
+func TestReadSourcePublishedSkipsDraft(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+	rw := &mockDraftContentRW{}
+
+	cAPIServerMock := newContentAPIServerMock(t, http.StatusOK, fromUppContent)
+	defer cAPIServerMock.Close()
+	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
+	assert.NoError(t, err)
+	cAPI := NewContentAPI(cAPIServerMock.URL, testBasicAuthUsername, testBasicAuthPassword, nil, testClient)
+
+	h := NewHandler(cAPI, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid", h.ReadContent)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s?source=published", contentUUID), nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "published", resp.Header.Get("X-Content-Source"))
+	rw.mock.AssertNotCalled(t, "Read", mock.Anything, mock.Anything)
+}
+
+func TestReadInvalidSource(t *testing.T) {
+	rw := &mockDraftContentRW{}
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid", h.ReadContent)
+
+	req := httptest.NewRequest("GET", "http://api.ft.com/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895?source=latest", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	rw.mock.AssertNotCalled(t, "Read", mock.Anything, mock.Anything)
+}
+
+func TestDiffContent(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	draft := `{"uuid": "83a201c6-60cd-11e7-91a7-502f7ee26895", "title": "Draft title", "type": "Article",
+		"lastModified": "2026-10-18T10:00:00Z", "draftReference": "tid_draft"}`
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(draft)), DraftMetadata{}, nil)
+
+	cAPIServerMock := newContentAPIServerMock(t, http.StatusOK, `{"id": "http://www.ft.com/thing/83a201c6-60cd-11e7-91a7-502f7ee26895", "title": "Published title", "type": "http://www.ft.com/ontology/content/Article", "byline": "FT",
+		"lastModified": "2026-10-17T10:00:00Z", "publishReference": "tid_publish"}`)
+	defer cAPIServerMock.Close()
+	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
+	assert.NoError(t, err)
+	cAPI := NewContentAPI(cAPIServerMock.URL, testBasicAuthUsername, testBasicAuthPassword, nil, testClient)
+
+	h := NewHandler(cAPI, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid", h.ReadContent)
+	r.Get("/drafts/content/:uuid/diff", h.DiffContent)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s/diff", contentUUID), nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	body, err := io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{
+		"uuid": "83a201c6-60cd-11e7-91a7-502f7ee26895",
+		"patch": [
+			{"op": "remove", "path": "/byline"},
+			{"op": "replace", "path": "/title", "value": "Draft title"}
+		],
+		"summary": {"added": [], "removed": ["byline"], "changed": ["title"]}
+	}`, string(body))
+	rw.mock.AssertExpectations(t)
+}
+
+func TestDiffContentInvalidUUID(t *testing.T) {
+	rw := &mockDraftContentRW{}
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/diff", h.DiffContent)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "http://api.ft.com/drafts/content/not-a-uuid/diff", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	rw.mock.AssertNotCalled(t, "Read", mock.Anything, mock.Anything)
+}
+
+func TestDiffContentDraftNotFound(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, ErrDraftNotFound)
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/diff", h.DiffContent)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s/diff", contentUUID), nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	body, err := io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "{\"message\": \"Draft not found\"}", string(body))
+	rw.mock.AssertExpectations(t)
+}
+
+func TestDiffContentPublishedNotFound(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(`{"title": "Draft title"}`)), DraftMetadata{}, nil)
+
+	cAPIServerMock := newContentAPIServerMock(t, http.StatusNotFound, "not found")
+	defer cAPIServerMock.Close()
+	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
+	assert.NoError(t, err)
+	cAPI := NewContentAPI(cAPIServerMock.URL, testBasicAuthUsername, testBasicAuthPassword, nil, testClient)
+
+	h := NewHandler(cAPI, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid/diff", h.DiffContent)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s/diff", contentUUID), nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	body, err := io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "{\"message\": \"Published content not found\"}", string(body))
+	rw.mock.AssertExpectations(t)
+}
+
 func TestWriteCCTNativeContent(t *testing.T) {
 	contentUUID := uuid.New().String()
 	draftBody := "{\"foo\":\"bar\"}"
@@ -267,7 +504,7 @@ func TestWriteCCTNativeContent(t *testing.T) {
 
 	rw := mockDraftContentRW{}
 	/* mock.AnythingOfType(...) doesn't work for interfaces: https://github.com/stretchr/testify/issues/519 */
-	rw.mock.On("Write", mock.Anything, contentUUID, &draftBody, headers).Return(nil)
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, headers).Return(nil)
 
 	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
 	r := vestigo.NewRouter()
@@ -307,7 +544,7 @@ func TestWriteSparkNativeContent(t *testing.T) {
 
 	rw := mockDraftContentRW{}
 	/* mock.AnythingOfType(...) doesn't work for interfaces: https://github.com/stretchr/testify/issues/519 */
-	rw.mock.On("Write", mock.Anything, contentUUID, &draftBody, headers).Return(nil)
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, headers).Return(nil)
 
 	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
 	r := vestigo.NewRouter()
@@ -328,6 +565,130 @@ func TestWriteSparkNativeContent(t *testing.T) {
 	rw.mock.AssertExpectations(t)
 }
 
+func TestWriteNativeContentNotifiesObservers(t *testing.T) {
+	contentUUID := uuid.New().String()
+	draftBody := "{\"foo\":\"bar\"}"
+
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+
+	AllowedContentTypes = map[string]struct{}{
+		contentTypeArticle: {},
+	}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(nil)
+
+	observer := &mockDraftObserver{}
+	observer.On("DraftChanged", mock.MatchedBy(func(event DraftEvent) bool {
+		return event.UUID == contentUUID &&
+			event.ContentType == contentTypeArticle &&
+			event.OriginSystemID == originIDcctTest &&
+			event.TransactionID == testTID &&
+			event.WriteReference == testTID &&
+			!event.Timestamp.IsZero()
+	})).Once()
+
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"), WithDraftObserver(observer))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+
+	req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(draftBody))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, contentTypeArticle)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	rw.mock.AssertExpectations(t)
+	observer.AssertExpectations(t)
+}
+
+func TestWriteNativeContentErrorDoesNotNotifyObservers(t *testing.T) {
+	contentUUID := uuid.New().String()
+
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+
+	AllowedContentTypes = map[string]struct{}{
+		contentTypeArticle: {},
+	}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("test error from writer"))
+	observer := &mockDraftObserver{}
+
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"), WithDraftObserver(observer))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+
+	req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader("{}"))
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, contentTypeArticle)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+	observer.AssertNotCalled(t, "DraftChanged", mock.Anything)
+}
+
+func TestReadInvalidDraftNotifiesValidationObservers(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	metadata := DraftMetadata{ContentType: contentTypeArticle, OriginSystemID: originIDcctTest, WriteReference: "tid_previous_write"}
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, metadata, ErrDraftNotValid)
+	observer := &mockDraftObserver{}
+	observer.On("DraftValidationFailed", mock.MatchedBy(func(event DraftEvent) bool {
+		return event.UUID == contentUUID &&
+			event.ContentType == contentTypeArticle &&
+			event.OriginSystemID == originIDcctTest &&
+			event.TransactionID == testTID &&
+			event.WriteReference == "tid_previous_write"
+	})).Once()
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"), WithDraftValidationObserver(observer))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid", h.ReadContent)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/content/%s", contentUUID), nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Result().StatusCode)
+	observer.AssertExpectations(t)
+}
+
+func TestReadSaturatedValidatorIsUnavailable(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	saturated := &url.Error{Op: "Post", URL: "http://upp-article-validator:8080/validate", Err: platform.ErrBulkheadFull}
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, saturated)
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid", h.ReadContent)
+	r.Get("/drafts/content/:uuid/diff", h.DiffContent)
+
+	for _, path := range []string{"/drafts/content/%s", "/drafts/content/%s/diff"} {
+		req := httptest.NewRequest("GET", fmt.Sprintf(path, contentUUID), nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	}
+}
+
 func TestWriteNativeContentInvalidUUID(t *testing.T) {
 	draftBody := "{\"foo\":\"bar\"}"
 
@@ -428,6 +789,228 @@ func TestWriteNativeContentInvalidContentType(t *testing.T) {
 	assert.Contains(t, response["message"], "Invalid content type", "error message")
 }
 
+func TestHappyNativeRead(t *testing.T) {
+	contentUUID := uuid.New().String()
+	nativeBody := `{"title":"Draft title"}`
+	metadata := DraftMetadata{
+		ContentType:    contentTypeArticle + "; version=1.0",
+		OriginSystemID: originIDcctTest,
+		LastModified:   "2018-02-21T14:25:00Z",
+		WriteReference: "tid_draft",
+	}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(nativeBody)), metadata, nil)
+
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/nativecontent/:uuid", h.ReadNativeContent)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	body, err := io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, nativeBody, string(body))
+	assert.Equal(t, contentTypeArticle+"; version=1.0", resp.Header.Get(contentTypeHeader))
+	assert.Equal(t, originIDcctTest, resp.Header.Get(originSystemIdHeader))
+	assert.Equal(t, "2018-02-21T14:25:00Z", resp.Header.Get("Last-Modified-RFC3339"))
+	assert.Equal(t, "tid_draft", resp.Header.Get("Write-Request-Id"))
+	rw.mock.AssertExpectations(t)
+}
+
+func TestNativeReadNotFound(t *testing.T) {
+	contentUUID := uuid.New().String()
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, ErrDraftNotFound)
+
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/nativecontent/:uuid", h.ReadNativeContent)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	body, err := io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "{\"message\": \"Draft not found\"}", string(body))
+	rw.mock.AssertExpectations(t)
+}
+
+func TestPatchNativeContent(t *testing.T) {
+	contentUUID := uuid.New().String()
+	nativeBody := `{"title":"Old title","byline":"FT","wordCount":120}`
+	expectedBody := `{"title":"New title","wordCount":120}`
+	metadata := DraftMetadata{ContentType: contentTypeArticle, OriginSystemID: originIDcctTest, WriteReference: "tid_draft"}
+
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(nativeBody)), metadata, nil)
+	rw.mock.On("Write", mock.Anything, contentUUID, expectedBody, map[string]string{
+		tidutils.TransactionIDHeader: testTID,
+		originSystemIdHeader:         originIDcctTest,
+		contentTypeHeader:            contentTypeArticle,
+		ifMatchHeader:                "tid_draft",
+	}).Return(nil)
+
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Patch("/drafts/nativecontent/:uuid", h.PatchNativeContent)
+
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(`{"title":"New title","byline":null}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, "application/merge-patch+json")
+	req.Header.Set("If-Match", `"tid_draft"`)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	resp := w.Result()
+	body, err := io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, expectedBody, string(body))
+	assert.Equal(t, contentTypeArticle, resp.Header.Get(contentTypeHeader))
+	assert.Equal(t, testTID, resp.Header.Get("Write-Request-Id"))
+	rw.mock.AssertExpectations(t)
+}
+
+func TestPatchNativeContentStaleDraftReference(t *testing.T) {
+	contentUUID := uuid.New().String()
+	metadata := DraftMetadata{ContentType: contentTypeArticle, OriginSystemID: originIDcctTest, WriteReference: "tid_newer_draft"}
+
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(`{"title":"Old title"}`)), metadata, nil)
+
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Patch("/drafts/nativecontent/:uuid", h.PatchNativeContent)
+
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(`{"title":"New title"}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, "application/merge-patch+json")
+	req.Header.Set("If-Match", "tid_draft")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Result().StatusCode)
+	rw.mock.AssertExpectations(t)
+	rw.mock.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchNativeContentModifiedConcurrently(t *testing.T) {
+	contentUUID := uuid.New().String()
+	metadata := DraftMetadata{ContentType: contentTypeArticle, OriginSystemID: originIDcctTest, WriteReference: "tid_draft"}
+
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(`{"title":"Old title"}`)), metadata, nil)
+	rw.mock.On("Write", mock.Anything, contentUUID, `{"title":"New title"}`, mock.Anything).Return(ErrDraftModified)
+
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Patch("/drafts/nativecontent/:uuid", h.PatchNativeContent)
+
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(`{"title":"New title"}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, "application/merge-patch+json")
+	req.Header.Set("If-Match", "tid_draft")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Result().StatusCode)
+	rw.mock.AssertExpectations(t)
+}
+
+func TestPatchNativeContentNotFound(t *testing.T) {
+	contentUUID := uuid.New().String()
+
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, ErrDraftNotFound)
+
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Patch("/drafts/nativecontent/:uuid", h.PatchNativeContent)
+
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(`{"title":"New title"}`))
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, "application/merge-patch+json")
+	req.Header.Set("If-Match", "tid_draft")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	rw.mock.AssertExpectations(t)
+}
+
+func TestPatchNativeContentInvalidRequests(t *testing.T) {
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+
+	tests := map[string]struct {
+		contentType    string
+		ifMatch        string
+		body           string
+		expectedStatus int
+	}{
+		"wrong content type": {contentTypeArticle, "tid_draft", `{"title":"New title"}`, http.StatusUnsupportedMediaType},
+		"missing If-Match":   {"application/merge-patch+json", "", `{"title":"New title"}`, http.StatusPreconditionRequired},
+		"malformed patch":    {"application/merge-patch+json", "tid_draft", `{"title":`, http.StatusBadRequest},
+		"non-object patch":   {"application/merge-patch+json", "tid_draft", `["title"]`, http.StatusBadRequest},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := mockDraftContentRW{}
+			h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+			r := vestigo.NewRouter()
+			r.Patch("/drafts/nativecontent/:uuid", h.PatchNativeContent)
+
+			req := httptest.NewRequest("PATCH", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", uuid.New().String()), strings.NewReader(test.body))
+			req.Header.Set(originSystemIdHeader, originIDcctTest)
+			req.Header.Set(contentTypeHeader, test.contentType)
+			req.Header.Set("If-Match", test.ifMatch)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Result().StatusCode)
+			rw.mock.AssertNotCalled(t, "ReadNative", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestWriteNativeContentWriteError(t *testing.T) {
 	contentUUID := uuid.New().String()
 	draftBody := "{\"foo\":\"bar\"}"
@@ -486,21 +1069,47 @@ func newContentAPIServerMock(t *testing.T, status int, body string) *httptest.Se
 	return ts
 }
 
-func (m *mockDraftContentRW) Read(ctx context.Context, contentUUID string, _ *logger.UPPLogger) (io.ReadCloser, error) {
+func (m *mockDraftContentRW) Read(ctx context.Context, contentUUID string, _ *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error) {
 	args := m.mock.Called(ctx, contentUUID)
 	var body io.ReadCloser
 	o := args.Get(0)
 	if o != nil {
 		body = o.(io.ReadCloser)
 	}
-	return body, args.Error(1)
+	return body, args.Get(1).(DraftMetadata), args.Error(2)
 }
 
-func (m *mockDraftContentRW) Write(ctx context.Context, contentUUID string, content *string, headers map[string]string, _ *logger.UPPLogger) error {
-	args := m.mock.Called(ctx, contentUUID, content, headers)
+func (m *mockDraftContentRW) ReadNative(ctx context.Context, contentUUID string, _ *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error) {
+	args := m.mock.Called(ctx, contentUUID)
+	var body io.ReadCloser
+	o := args.Get(0)
+	if o != nil {
+		body = o.(io.ReadCloser)
+	}
+	return body, args.Get(1).(DraftMetadata), args.Error(2)
+}
+
+func (m *mockDraftContentRW) Write(ctx context.Context, contentUUID string, content io.Reader, headers map[string]string, _ *logger.UPPLogger) error {
+	body, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	args := m.mock.Called(ctx, contentUUID, string(body), headers)
 	return args.Error(0)
 }
 
+type mockDraftObserver struct {
+	mock.Mock
+}
+
+func (m *mockDraftObserver) DraftChanged(_ context.Context, event DraftEvent) {
+	m.Called(event)
+}
+
+func (m *mockDraftObserver) DraftValidationFailed(_ context.Context, event DraftEvent) {
+	m.Called(event)
+}
+
 func (m *mockDraftContentRW) GTG() error {
 	return nil
 }
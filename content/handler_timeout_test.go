@@ -3,6 +3,7 @@ package content
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -137,7 +138,7 @@ func TestNativeWriteTimeout(t *testing.T) {
 	server := httptest.NewServer(r)
 	defer server.Close()
 
-	request, _ := http.NewRequest(http.MethodPut, server.URL+"/drafts/nativecontent/"+contentUUID, nil)
+	request, _ := http.NewRequest(http.MethodPut, server.URL+"/drafts/nativecontent/"+contentUUID, strings.NewReader("{}"))
 	request.Header.Set(tidutils.TransactionIDHeader, testTID)
 	request.Header.Set(originSystemIdHeader, originIDcctTest)
 	request.Header.Set(contentTypeHeader, contentTypeArticle)
@@ -15,6 +15,7 @@ func newHttpRequest(ctx context.Context, method string, url string, payload io.R
 		if tidErr == nil {
 			req.Header.Set(tidutils.TransactionIDHeader, tid)
 		}
+		injectTraceContext(ctx, req.Header)
 		req = req.WithContext(ctx)
 	}
 	return
@@ -0,0 +1,194 @@
+package content
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/go-logger/v2"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	defaultIdempotencyMaxKeys = 10000
+)
+
+// IdempotencyRecord is the outcome of a draft write made with an Idempotency-Key.
+// Fingerprint identifies the write, so that a key reused for a different draft is detected.
+type IdempotencyRecord struct {
+	Fingerprint string
+	Status      int
+}
+
+// IdempotencyStore keeps the outcome of the draft writes made with an Idempotency-Key for a while.
+type IdempotencyStore interface {
+	// Get returns the record stored under the key, false if there is none or it has expired.
+	Get(ctx context.Context, key string) (IdempotencyRecord, bool, error)
+	Put(ctx context.Context, key string, record IdempotencyRecord) error
+}
+
+// WithIdempotencyStore lets the clients of the Handler retry a draft write with the same Idempotency-Key
+// without writing the draft, nor notifying the draft observers, again.
+func WithIdempotencyStore(store IdempotencyStore) HandlerOption {
+	return func(h *Handler) {
+		h.idempotency = store
+	}
+}
+
+// idempotentWrite is a draft write made with an Idempotency-Key. A nil idempotentWrite does nothing.
+type idempotentWrite struct {
+	store       IdempotencyStore
+	key         string
+	fingerprint string
+	unlock      func()
+}
+
+// startIdempotentWrite answers a draft write with the outcome of the original write made with the same Idempotency-Key,
+// or 422 Unprocessable Entity if the key was used for a different write, and then returns false.
+// Otherwise, the returned write must be ended once completed. Writes with the same key are serialised,
+// so that a retry waits for the outcome of the original write.
+func (h *Handler) startIdempotentWrite(w http.ResponseWriter, r *http.Request, requestLog *logger.LogEntry, contentUUID string, fingerprint string) (*idempotentWrite, bool) {
+	key := r.Header.Get(idempotencyKeyHeader)
+	if h.idempotency == nil || key == "" {
+		return nil, true
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		writeMessage(w, fmt.Sprintf("Invalid %v, it must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength), http.StatusBadRequest)
+		return nil, false
+	}
+
+	lockCtx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+	unlock, err := h.locks.lock(lockCtx, contentUUID+"/"+key)
+	if err != nil {
+		requestLog.WithField(idempotencyKeyHeader, key).Warn("Timed out waiting for the original draft write with the Idempotency-Key")
+		writeMessage(w, fmt.Sprintf("A draft write with the %v %v is still in progress", idempotencyKeyHeader, key), http.StatusConflict)
+		return nil, false
+	}
+
+	iw := &idempotentWrite{
+		store:       h.idempotency,
+		key:         contentUUID + "/" + key,
+		fingerprint: fingerprint,
+		unlock:      unlock,
+	}
+
+	record, found, err := iw.store.Get(r.Context(), iw.key)
+	if err != nil {
+		requestLog.WithError(err).Warn("Unable to look up the Idempotency-Key, the draft is written again")
+		return iw, true
+	}
+	if !found {
+		return iw, true
+	}
+
+	iw.end()
+	if record.Fingerprint != fingerprint {
+		requestLog.WithField(idempotencyKeyHeader, key).Warn("Idempotency-Key reused for a different draft write")
+		writeMessage(w, fmt.Sprintf("%v %v was used for a different draft write", idempotencyKeyHeader, key), http.StatusUnprocessableEntity)
+		return nil, false
+	}
+
+	requestLog.WithField(idempotencyKeyHeader, key).Info("Draft write already made with the Idempotency-Key, replaying its outcome")
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(record.Status)
+	return nil, false
+}
+
+// succeeded records the outcome of the write. Failed writes are not recorded, so that they can be retried.
+func (iw *idempotentWrite) succeeded(ctx context.Context, requestLog *logger.LogEntry, status int) {
+	if iw == nil {
+		return
+	}
+	if err := iw.store.Put(ctx, iw.key, IdempotencyRecord{Fingerprint: iw.fingerprint, Status: status}); err != nil {
+		requestLog.WithError(err).Warn("Unable to record the outcome of the draft write for its Idempotency-Key")
+	}
+}
+
+func (iw *idempotentWrite) end() {
+	if iw == nil {
+		return
+	}
+	iw.unlock()
+}
+
+// draftWriteFingerprint identifies a draft write by everything that is stored.
+func draftWriteFingerprint(contentType string, originSystemID string, draft []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n", contentType, originSystemID)
+	hash.Write(draft)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type memoryIdempotencyStore struct {
+	window  time.Duration
+	maxKeys int
+
+	mu      sync.Mutex
+	records map[string]*list.Element
+	// order holds the records from the oldest to the newest, which is also the order they expire in
+	order *list.List
+}
+
+type memoryIdempotencyRecord struct {
+	key       string
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore returns an IdempotencyStore keeping records for the window in memory,
+// evicting the oldest ones beyond maxKeys.
+func NewMemoryIdempotencyStore(window time.Duration, maxKeys int) IdempotencyStore {
+	if maxKeys <= 0 {
+		maxKeys = defaultIdempotencyMaxKeys
+	}
+	return &memoryIdempotencyStore{
+		window:  window,
+		maxKeys: maxKeys,
+		records: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (s *memoryIdempotencyStore) Get(_ context.Context, key string) (IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, found := s.records[key]
+	if !found {
+		return IdempotencyRecord{}, false, nil
+	}
+	stored := e.Value.(*memoryIdempotencyRecord)
+	if time.Now().After(stored.expiresAt) {
+		return IdempotencyRecord{}, false, nil
+	}
+	return stored.record, true, nil
+}
+
+func (s *memoryIdempotencyStore) Put(_ context.Context, key string, record IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, found := s.records[key]; found {
+		s.order.Remove(e)
+	}
+	s.records[key] = s.order.PushBack(&memoryIdempotencyRecord{key: key, record: record, expiresAt: time.Now().Add(s.window)})
+
+	now := time.Now()
+	for e := s.order.Front(); e != nil; e = s.order.Front() {
+		stored := e.Value.(*memoryIdempotencyRecord)
+		if len(s.records) <= s.maxKeys && now.Before(stored.expiresAt) {
+			break
+		}
+		s.order.Remove(e)
+		delete(s.records, stored.key)
+	}
+	return nil
+}
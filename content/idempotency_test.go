@@ -0,0 +1,173 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotentRouter(rw DraftContentRW, observer DraftObserver) *vestigo.Router {
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+	AllowedContentTypes = map[string]struct{}{
+		contentTypeArticle: {},
+	}
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"),
+		WithIdempotencyStore(NewMemoryIdempotencyStore(time.Minute, 10)), WithDraftObserver(observer))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+	return r
+}
+
+func idempotentPut(r http.Handler, contentUUID string, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(body))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, contentTypeArticle)
+	if key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	store := NewMemoryIdempotencyStore(time.Minute, 2)
+	ctx := context.TODO()
+
+	require.NoError(t, store.Put(ctx, "a", IdempotencyRecord{Fingerprint: "fa", Status: http.StatusOK}))
+	require.NoError(t, store.Put(ctx, "b", IdempotencyRecord{Fingerprint: "fb", Status: http.StatusOK}))
+
+	record, found, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, IdempotencyRecord{Fingerprint: "fa", Status: http.StatusOK}, record)
+
+	require.NoError(t, store.Put(ctx, "c", IdempotencyRecord{Fingerprint: "fc", Status: http.StatusOK}))
+	_, found, _ = store.Get(ctx, "a")
+	assert.False(t, found, "the oldest record is evicted")
+	_, found, _ = store.Get(ctx, "c")
+	assert.True(t, found)
+}
+
+func TestMemoryIdempotencyStoreExpires(t *testing.T) {
+	store := NewMemoryIdempotencyStore(time.Millisecond, 10)
+	ctx := context.TODO()
+
+	require.NoError(t, store.Put(ctx, "a", IdempotencyRecord{Fingerprint: "fa", Status: http.StatusOK}))
+	time.Sleep(5 * time.Millisecond)
+
+	_, found, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestWriteNativeContentIdempotencyKeyReplaysOutcome(t *testing.T) {
+	contentUUID := uuid.New().String()
+	draftBody := `{"title":"Draft"}`
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(nil).Once()
+	observer := &mockDraftObserver{}
+	observer.On("DraftChanged", mock.Anything).Return().Once()
+	r := newIdempotentRouter(&rw, observer)
+
+	w := idempotentPut(r, contentUUID, "save-1", draftBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(idempotentReplayedHeader))
+
+	w = idempotentPut(r, contentUUID, "save-1", draftBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get(idempotentReplayedHeader))
+
+	rw.mock.AssertExpectations(t)
+	observer.AssertExpectations(t)
+}
+
+func TestWriteNativeContentIdempotencyKeyReusedForAnotherDraft(t *testing.T) {
+	contentUUID := uuid.New().String()
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, `{"title":"Draft"}`, mock.Anything).Return(nil).Once()
+	observer := &mockDraftObserver{}
+	observer.On("DraftChanged", mock.Anything).Return()
+	r := newIdempotentRouter(&rw, observer)
+
+	assert.Equal(t, http.StatusOK, idempotentPut(r, contentUUID, "save-1", `{"title":"Draft"}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, idempotentPut(r, contentUUID, "save-1", `{"title":"Another draft"}`).Code)
+	rw.mock.AssertExpectations(t)
+}
+
+func TestWriteNativeContentIdempotencyKeyScopes(t *testing.T) {
+	contentUUID := uuid.New().String()
+	otherUUID := uuid.New().String()
+	draftBody := `{"title":"Draft"}`
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(nil).Twice()
+	rw.mock.On("Write", mock.Anything, otherUUID, draftBody, mock.Anything).Return(nil).Once()
+	observer := &mockDraftObserver{}
+	observer.On("DraftChanged", mock.Anything).Return()
+	r := newIdempotentRouter(&rw, observer)
+
+	assert.Equal(t, http.StatusOK, idempotentPut(r, contentUUID, "save-1", draftBody).Code)
+	assert.Equal(t, http.StatusOK, idempotentPut(r, otherUUID, "save-1", draftBody).Code, "keys are scoped by draft")
+	assert.Equal(t, http.StatusOK, idempotentPut(r, contentUUID, "", draftBody).Code, "writes without a key are always made")
+	rw.mock.AssertExpectations(t)
+}
+
+func TestWriteNativeContentIdempotencyKeyRetriesFailedWrites(t *testing.T) {
+	contentUUID := uuid.New().String()
+	draftBody := `{"title":"Draft"}`
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(fmt.Errorf("RW unavailable")).Once()
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(nil).Once()
+	observer := &mockDraftObserver{}
+	observer.On("DraftChanged", mock.Anything).Return()
+	r := newIdempotentRouter(&rw, observer)
+
+	assert.Equal(t, http.StatusInternalServerError, idempotentPut(r, contentUUID, "save-1", draftBody).Code)
+	assert.Equal(t, http.StatusOK, idempotentPut(r, contentUUID, "save-1", draftBody).Code)
+	rw.mock.AssertExpectations(t)
+}
+
+func TestWriteNativeContentConcurrentIdempotentRetries(t *testing.T) {
+	contentUUID := uuid.New().String()
+	draftBody := `{"title":"Draft"}`
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).
+		Run(func(mock.Arguments) { time.Sleep(10 * time.Millisecond) }).Return(nil).Once()
+	observer := &mockDraftObserver{}
+	observer.On("DraftChanged", mock.Anything).Return()
+	r := newIdempotentRouter(&rw, observer)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, idempotentPut(r, contentUUID, "save-1", draftBody).Code)
+		}()
+	}
+	wg.Wait()
+
+	rw.mock.AssertExpectations(t)
+}
@@ -0,0 +1,153 @@
+package content
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	patchOpAdd     = "add"
+	patchOpRemove  = "remove"
+	patchOpReplace = "replace"
+)
+
+// diffIgnoredFields are the top level metadata fields of the mapped draft and of the published content,
+// which differ whatever the draft changes and are not changed by publishing it.
+var diffIgnoredFields = []string{"lastModified", "draftReference", "publishReference"}
+
+// patchOperation is a single RFC 6902 JSON Patch operation.
+type patchOperation struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+func (op patchOperation) MarshalJSON() ([]byte, error) {
+	if op.Op == patchOpRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	}
+
+	return json.Marshal(struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{op.Op, op.Path, op.Value})
+}
+
+// diffSummary lists the top level fields touched by a JSON Patch.
+type diffSummary struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// withoutDiffIgnoredFields returns a copy of the document without its diffIgnoredFields.
+func withoutDiffIgnoredFields(doc map[string]interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(doc))
+	for key, value := range doc {
+		fields[key] = value
+	}
+	for _, key := range diffIgnoredFields {
+		delete(fields, key)
+	}
+	return fields
+}
+
+// diffJSON returns the JSON Patch turning the from document into the to document.
+// Arrays of different lengths are replaced as a whole rather than diffed element by element.
+func diffJSON(from, to interface{}) []patchOperation {
+	return diffValues("", from, to, []patchOperation{})
+}
+
+func diffValues(path string, from, to interface{}, ops []patchOperation) []patchOperation {
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		return diffObjects(path, fromMap, toMap, ops)
+	}
+
+	fromArray, fromIsArray := from.([]interface{})
+	toArray, toIsArray := to.([]interface{})
+	if fromIsArray && toIsArray && len(fromArray) == len(toArray) {
+		for i := range fromArray {
+			ops = diffValues(path+"/"+strconv.Itoa(i), fromArray[i], toArray[i], ops)
+		}
+		return ops
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		ops = append(ops, patchOperation{Op: patchOpReplace, Path: path, Value: to})
+	}
+	return ops
+}
+
+func diffObjects(path string, from, to map[string]interface{}, ops []patchOperation) []patchOperation {
+	for _, key := range sortedKeys(from) {
+		if _, found := to[key]; !found {
+			ops = append(ops, patchOperation{Op: patchOpRemove, Path: path + "/" + escapeJSONPointer(key)})
+		}
+	}
+
+	for _, key := range sortedKeys(to) {
+		keyPath := path + "/" + escapeJSONPointer(key)
+		fromValue, found := from[key]
+		if !found {
+			ops = append(ops, patchOperation{Op: patchOpAdd, Path: keyPath, Value: to[key]})
+			continue
+		}
+		ops = diffValues(keyPath, fromValue, to[key], ops)
+	}
+
+	return ops
+}
+
+func summariseDiff(ops []patchOperation) diffSummary {
+	summary := diffSummary{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	seen := map[string]struct{}{}
+
+	for _, op := range ops {
+		segments := strings.SplitN(strings.TrimPrefix(op.Path, "/"), "/", 2)
+		field := unescapeJSONPointer(segments[0])
+		if _, found := seen[field]; found {
+			continue
+		}
+		seen[field] = struct{}{}
+
+		switch {
+		case len(segments) == 1 && op.Op == patchOpAdd:
+			summary.Added = append(summary.Added, field)
+		case len(segments) == 1 && op.Op == patchOpRemove:
+			summary.Removed = append(summary.Removed, field)
+		default:
+			summary.Changed = append(summary.Changed, field)
+		}
+	}
+
+	sort.Strings(summary.Added)
+	sort.Strings(summary.Removed)
+	sort.Strings(summary.Changed)
+	return summary
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
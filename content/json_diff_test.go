@@ -0,0 +1,64 @@
+package content
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffJSON(t *testing.T) {
+	from := map[string]interface{}{
+		"title":    "Old title",
+		"byline":   "FT reporter",
+		"brands":   []interface{}{"a", "b"},
+		"standout": map[string]interface{}{"scoop": false, "editorsChoice": true},
+		"a/b":      "slash",
+	}
+	to := map[string]interface{}{
+		"title":    "New title",
+		"body":     "<body/>",
+		"brands":   []interface{}{"a", "c"},
+		"standout": map[string]interface{}{"scoop": true, "editorsChoice": true},
+		"a/b":      nil,
+	}
+
+	ops := diffJSON(from, to)
+
+	actual, err := json.Marshal(ops)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "remove", "path": "/byline"},
+		{"op": "replace", "path": "/a~1b", "value": null},
+		{"op": "add", "path": "/body", "value": "<body/>"},
+		{"op": "replace", "path": "/brands/1", "value": "c"},
+		{"op": "replace", "path": "/standout/scoop", "value": true},
+		{"op": "replace", "path": "/title", "value": "New title"}
+	]`, string(actual))
+
+	summary := summariseDiff(ops)
+	assert.Equal(t, []string{"body"}, summary.Added)
+	assert.Equal(t, []string{"byline"}, summary.Removed)
+	assert.Equal(t, []string{"a/b", "brands", "standout", "title"}, summary.Changed)
+}
+
+func TestDiffJSONArrayLengthChange(t *testing.T) {
+	from := map[string]interface{}{"brands": []interface{}{"a"}}
+	to := map[string]interface{}{"brands": []interface{}{"a", "b"}}
+
+	ops := diffJSON(from, to)
+
+	assert.Equal(t, []patchOperation{{Op: "replace", Path: "/brands", Value: []interface{}{"a", "b"}}}, ops)
+}
+
+func TestDiffJSONNoChanges(t *testing.T) {
+	doc := map[string]interface{}{"title": "Same", "brands": []interface{}{"a"}}
+
+	ops := diffJSON(doc, doc)
+
+	assert.Empty(t, ops)
+	summary := summariseDiff(ops)
+	assert.Empty(t, summary.Added)
+	assert.Empty(t, summary.Removed)
+	assert.Empty(t, summary.Changed)
+}
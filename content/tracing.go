@@ -0,0 +1,44 @@
+package content
+
+import (
+	"context"
+	"net/http"
+
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const transactionIDAttribute = "ft.transaction_id"
+
+const tracerName = "github.com/Financial-Times/draft-content-api/content"
+
+// startSpan starts a span carrying the transaction ID of the context, so that traces can be found from the logs.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tid, _ := tidutils.GetTransactionIDFromContext(ctx)
+	attrs = append(attrs, attribute.String(transactionIDAttribute, tid))
+	// the tracer is looked up on every span, so that spans follow the current global tracer provider
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records the error of the traced operation, if any, and ends its span.
+func endSpan(span trace.Span, err error) {
+	recordSpanError(span, err)
+	span.End()
+}
+
+// recordSpanError marks the span as failed with the error, if any.
+func recordSpanError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// injectTraceContext propagates the trace of the context to an upstream request.
+func injectTraceContext(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
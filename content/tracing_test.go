@@ -0,0 +1,104 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func TestContentAPIGetPropagatesTraceContext(t *testing.T) {
+	recorder := recordSpans(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, parent := otel.Tracer("test").Start(tidutils.TransactionAwareContext(context.TODO(), testTID), "parent")
+	api := NewContentAPI(server.URL, testBasicAuthUsername, testBasicAuthPassword, nil, http.DefaultClient)
+	resp, err := api.Get(ctx, "83a201c6-60cd-11e7-91a7-502f7ee26895", logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	assert.Contains(t, traceparent, parent.SpanContext().TraceID().String())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "API.Get", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), attribute.String(transactionIDAttribute, testTID))
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusOK))
+}
+
+func TestNewContextFromRequestExtractsTraceContext(t *testing.T) {
+	recordSpans(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/drafts/content/83a201c6-60cd-11e7-91a7-502f7ee26895", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+
+	ctx := newContextFromRequest(req)
+	_, span := startSpan(ctx, "test")
+	span.End()
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	tid, err := tidutils.GetTransactionIDFromContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, testTID, tid)
+}
+
+func TestReadContentRecordsErrors(t *testing.T) {
+	recorder := recordSpans(t)
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+	rw := &mockDraftContentRW{}
+	rw.mock.On("Read", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, errors.New("draft store unavailable"))
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/content/:uuid", h.ReadContent)
+
+	req := httptest.NewRequest(http.MethodGet, "/drafts/content/"+contentUUID, nil)
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "Handler.ReadContent", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "draft store unavailable", spans[0].Status().Description)
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+}
@@ -0,0 +1,136 @@
+package content
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+)
+
+// UUIDMismatchError is returned for a draft whose own UUID is not the UUID it is written under.
+type UUIDMismatchError struct {
+	Pointer  string
+	BodyUUID string
+	PathUUID string
+}
+
+func (e *UUIDMismatchError) Error() string {
+	return fmt.Sprintf("the draft uuid %v at %v does not match the uuid %v of the path", e.BodyUUID, e.Pointer, e.PathUUID)
+}
+
+// WithUUIDPointers checks that the drafts written to the Handler carry the UUID they are written under,
+// at the JSON pointer (RFC 6901) of their content type, e.g. /uuid. Drafts of the other content types are not checked.
+func WithUUIDPointers(pointers map[string]string) HandlerOption {
+	return func(h *Handler) {
+		h.uuidPointers = pointers
+	}
+}
+
+// contentUUIDParam returns the canonical, lower case, form of the uuid path parameter,
+// so that a draft is stored and found under the same UUID whatever the case used by clients.
+func contentUUIDParam(r *http.Request) string {
+	return strings.ToLower(vestigo.Param(r, "uuid"))
+}
+
+// legacyUUIDParam returns the uuid path parameter as given, when it is not in its canonical form.
+// Drafts written before UUIDs were canonicalised may be stored under that form only.
+func legacyUUIDParam(r *http.Request) (string, bool) {
+	given := vestigo.Param(r, "uuid")
+	return given, given != strings.ToLower(given)
+}
+
+type draftReader func(ctx context.Context, contentUUID string, log *logger.UPPLogger) (io.ReadCloser, DraftMetadata, error)
+
+// readCanonicalDraft reads the draft stored under contentUUID, the canonical UUID of the request, falling back to
+// the UUID as given in the path when no draft is found, and returns the UUID the draft is stored under.
+func (h *Handler) readCanonicalDraft(ctx context.Context, r *http.Request, contentUUID string, read draftReader) (io.ReadCloser, DraftMetadata, string, error) {
+	content, metadata, err := read(ctx, contentUUID, h.log)
+	if err != ErrDraftNotFound {
+		return content, metadata, contentUUID, err
+	}
+
+	legacyUUID, found := legacyUUIDParam(r)
+	if !found {
+		return content, metadata, contentUUID, err
+	}
+	content, metadata, err = read(ctx, legacyUUID, h.log)
+	if err == nil {
+		h.log.WithField(tidutils.TransactionIDHeader, ctx.Value(tidutils.TransactionIDHeader)).WithField("uuid", legacyUUID).
+			Warn("Draft found under a non canonical uuid, it is stored under the canonical one on its next write")
+	}
+	return content, metadata, legacyUUID, err
+}
+
+// checkDraftUUID returns a *UUIDMismatchError if the UUID of a draft is not contentUUID.
+// Drafts without a UUID at the pointer of their content type are accepted.
+func (h *Handler) checkDraftUUID(contentType string, contentUUID string, draft []byte) error {
+	pointer, found := h.uuidPointers[stripMediaTypeParameters(contentType)]
+	if !found || pointer == "" {
+		return nil
+	}
+
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(draft))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+
+	value, found := resolveJSONPointer(doc, pointer)
+	if !found || value == nil {
+		return nil
+	}
+
+	bodyUUID, ok := value.(string)
+	if !ok || !uuidMatches(bodyUUID, contentUUID) {
+		return &UUIDMismatchError{Pointer: pointer, BodyUUID: fmt.Sprint(value), PathUUID: contentUUID}
+	}
+	return nil
+}
+
+// uuidMatches compares UUIDs regardless of their case. The body UUID may also be the last segment of a URI,
+// as in the id of UPP content, http://www.ft.com/thing/<uuid>.
+func uuidMatches(bodyUUID string, contentUUID string) bool {
+	if i := strings.LastIndex(bodyUUID, "/"); i >= 0 {
+		bodyUUID = bodyUUID[i+1:]
+	}
+	return strings.EqualFold(bodyUUID, contentUUID)
+}
+
+func resolveJSONPointer(doc interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = unescapeJSONPointer(token)
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, found := node[token]
+			if !found {
+				return nil, false
+			}
+			doc = value
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			doc = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return doc, true
+}
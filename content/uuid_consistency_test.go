@@ -0,0 +1,205 @@
+package content
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testUUIDPointer = "/uuid"
+
+func newUUIDCheckingRouter(rw DraftContentRW) *vestigo.Router {
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+	AllowedContentTypes = map[string]struct{}{
+		contentTypeArticle: {},
+		"application/json": {},
+	}
+
+	h := NewHandler(nil, rw, testTimeout, logger.NewUPPLogger("test logger", "debug"),
+		WithUUIDPointers(map[string]string{contentTypeArticle: testUUIDPointer}))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+	r.Patch("/drafts/nativecontent/:uuid", h.PatchNativeContent)
+	return r
+}
+
+func TestResolveJSONPointer(t *testing.T) {
+	doc := map[string]interface{}{
+		"uuid": "83a201c6-60cd-11e7-91a7-502f7ee26895",
+		"a/b":  map[string]interface{}{"m~n": "escaped"},
+		"list": []interface{}{"first", map[string]interface{}{"id": "second"}},
+	}
+
+	tests := map[string]struct {
+		pointer string
+		value   interface{}
+		found   bool
+	}{
+		"top level field":  {pointer: "/uuid", value: "83a201c6-60cd-11e7-91a7-502f7ee26895", found: true},
+		"escaped tokens":   {pointer: "/a~1b/m~0n", value: "escaped", found: true},
+		"array index":      {pointer: "/list/1/id", value: "second", found: true},
+		"missing field":    {pointer: "/id"},
+		"index past end":   {pointer: "/list/2"},
+		"relative pointer": {pointer: "uuid"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			value, found := resolveJSONPointer(doc, test.pointer)
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.value, value)
+		})
+	}
+}
+
+func TestUUIDMatches(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+	assert.True(t, uuidMatches(contentUUID, contentUUID))
+	assert.True(t, uuidMatches(strings.ToUpper(contentUUID), contentUUID))
+	assert.True(t, uuidMatches("http://www.ft.com/thing/"+contentUUID, contentUUID))
+	assert.False(t, uuidMatches("0e7ad51c-4a0b-11e7-8b62-0c6e6bd7e9a4", contentUUID))
+}
+
+func TestWriteNativeContentUUIDMismatch(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+	rw := mockDraftContentRW{}
+	r := newUUIDCheckingRouter(&rw)
+
+	req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(`{"uuid":"0e7ad51c-4a0b-11e7-8b62-0c6e6bd7e9a4"}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, contentTypeArticle)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "does not match the uuid 83a201c6-60cd-11e7-91a7-502f7ee26895 of the path")
+	rw.mock.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWriteNativeContentCanonicalUUID(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+
+	tests := map[string]struct {
+		contentType string
+		body        string
+	}{
+		"matching draft uuid":      {contentType: contentTypeArticle, body: `{"uuid":"83A201C6-60CD-11E7-91A7-502F7EE26895"}`},
+		"draft without uuid":       {contentType: contentTypeArticle, body: `{"title":"Draft"}`},
+		"content type not checked": {contentType: "application/json", body: `{"uuid":"0e7ad51c-4a0b-11e7-8b62-0c6e6bd7e9a4"}`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rw := mockDraftContentRW{}
+			rw.mock.On("Write", mock.Anything, contentUUID, test.body, mock.Anything).Return(nil)
+			r := newUUIDCheckingRouter(&rw)
+
+			req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", strings.ToUpper(contentUUID)), strings.NewReader(test.body))
+			req.Header.Set(tidutils.TransactionIDHeader, testTID)
+			req.Header.Set(originSystemIdHeader, originIDcctTest)
+			req.Header.Set(contentTypeHeader, test.contentType)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			rw.mock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatchNativeContentUUIDMismatch(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	metadata := DraftMetadata{ContentType: contentTypeArticle, OriginSystemID: originIDcctTest, WriteReference: "tid_draft"}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(`{"uuid":"83a201c6-60cd-11e7-91a7-502f7ee26895"}`)), metadata, nil)
+	r := newUUIDCheckingRouter(&rw)
+
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(`{"uuid":"0e7ad51c-4a0b-11e7-8b62-0c6e6bd7e9a4"}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, "application/merge-patch+json")
+	req.Header.Set("If-Match", `"tid_draft"`)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	rw.mock.AssertNotCalled(t, "Write", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReadNativeContentCanonicalUUID(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	metadata := DraftMetadata{ContentType: contentTypeArticle, OriginSystemID: originIDcctTest}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(io.NopCloser(strings.NewReader(`{"title":"Draft"}`)), metadata, nil)
+
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/nativecontent/:uuid", h.ReadNativeContent)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/drafts/nativecontent/%s", strings.ToUpper(contentUUID)), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	rw.mock.AssertExpectations(t)
+}
+
+func TestReadNativeContentLegacyUUID(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	legacyUUID := strings.ToUpper(contentUUID)
+	metadata := DraftMetadata{ContentType: contentTypeArticle, OriginSystemID: originIDcctTest}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, ErrDraftNotFound)
+	rw.mock.On("ReadNative", mock.Anything, legacyUUID).Return(io.NopCloser(strings.NewReader(`{"title":"Draft"}`)), metadata, nil)
+
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"))
+	r := vestigo.NewRouter()
+	r.Get("/drafts/nativecontent/:uuid", h.ReadNativeContent)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/drafts/nativecontent/%s", legacyUUID), nil))
+	assert.Equal(t, http.StatusOK, w.Code, "a draft stored under an upper case uuid is still found")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/drafts/nativecontent/%s", contentUUID), nil))
+	assert.Equal(t, http.StatusNotFound, w.Code, "the lower case uuid has no fallback")
+}
+
+func TestPatchNativeContentLegacyUUID(t *testing.T) {
+	contentUUID := "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	legacyUUID := strings.ToUpper(contentUUID)
+	metadata := DraftMetadata{ContentType: contentTypeArticle, OriginSystemID: originIDcctTest, WriteReference: "tid_draft"}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("ReadNative", mock.Anything, contentUUID).Return(nil, DraftMetadata{}, ErrDraftNotFound)
+	rw.mock.On("ReadNative", mock.Anything, legacyUUID).Return(io.NopCloser(strings.NewReader(`{"uuid":"`+legacyUUID+`"}`)), metadata, nil)
+	rw.mock.On("Write", mock.Anything, legacyUUID, mock.Anything, mock.Anything).Return(nil)
+	r := newUUIDCheckingRouter(&rw)
+
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", legacyUUID), strings.NewReader(`{"title":"Patched"}`))
+	req.Header.Set(tidutils.TransactionIDHeader, testTID)
+	req.Header.Set(originSystemIdHeader, originIDcctTest)
+	req.Header.Set(contentTypeHeader, "application/merge-patch+json")
+	req.Header.Set("If-Match", `"tid_draft"`)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	rw.mock.AssertExpectations(t)
+}
@@ -0,0 +1,181 @@
+package content
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/config"
+	"github.com/Financial-Times/go-logger/v2"
+	"golang.org/x/time/rate"
+)
+
+const (
+	WriteLimitRate     = "rate"
+	WriteLimitInFlight = "in_flight"
+	WriteLimitUUIDRate = "uuid_rate"
+
+	uuidLimitersSweepInterval = time.Minute
+)
+
+// WriteLimitObserver is notified of the draft writes rejected by a WriteLimiter, with the limit they exceeded.
+type WriteLimitObserver interface {
+	DraftWriteLimited(originSystemID string, limit string)
+}
+
+// WriteLimiter keeps a runaway client, e.g. a CMS autosave loop, from flooding the draft store.
+// Writes are rate limited and their concurrency is capped by origin system, and optionally rate limited by draft.
+type WriteLimiter struct {
+	cfg      config.WriteLimitsConfig
+	observer WriteLimitObserver
+
+	mu        sync.Mutex
+	origins   map[string]*originWriteQuota
+	uuids     map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+type originWriteQuota struct {
+	limiter     *rate.Limiter
+	inFlight    int
+	maxInFlight int
+}
+
+// NewWriteLimiter returns a limiter of the draft writes. The observer may be nil.
+func NewWriteLimiter(cfg config.WriteLimitsConfig, observer WriteLimitObserver) *WriteLimiter {
+	return &WriteLimiter{
+		cfg:       cfg,
+		observer:  observer,
+		origins:   map[string]*originWriteQuota{},
+		uuids:     map[string]*rate.Limiter{},
+		lastSweep: time.Now(),
+	}
+}
+
+// WithWriteLimiter limits the draft writes of the Handler.
+func WithWriteLimiter(limiter *WriteLimiter) HandlerOption {
+	return func(h *Handler) {
+		h.writeLimiter = limiter
+	}
+}
+
+// admitWrite answers 429 Too Many Requests to a write exceeding the limits.
+// The returned function must be called once an admitted write has completed.
+func (h *Handler) admitWrite(w http.ResponseWriter, writeLog *logger.LogEntry, originSystemID string, contentUUID string) (func(), bool) {
+	if h.writeLimiter == nil {
+		return func() {}, true
+	}
+
+	release, limit, retryAfter := h.writeLimiter.acquire(originSystemID, contentUUID)
+	if release == nil {
+		writeLog.WithField("limit", limit).Warn("Draft write rejected by the write limits")
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		writeMessage(w, fmt.Sprintf("Too many draft writes from %v, %v limit exceeded", originSystemID, limit), http.StatusTooManyRequests)
+		return nil, false
+	}
+	return release, true
+}
+
+// acquire admits a write of the draft by the origin system and returns the function ending it.
+// A rejected write gets the limit it exceeded and when it may be retried.
+func (l *WriteLimiter) acquire(originSystemID string, contentUUID string) (release func(), limit string, retryAfter time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	quota := l.originQuota(originSystemID)
+	if quota.maxInFlight > 0 && quota.inFlight >= quota.maxInFlight {
+		return l.rejected(originSystemID, WriteLimitInFlight, time.Second)
+	}
+
+	originReservation := quota.limiter.ReserveN(now, 1)
+	if delay := reservationDelay(originReservation, now); delay > 0 {
+		originReservation.CancelAt(now)
+		return l.rejected(originSystemID, WriteLimitRate, delay)
+	}
+
+	if uuidLimiter := l.uuidLimiter(contentUUID, now); uuidLimiter != nil {
+		uuidReservation := uuidLimiter.ReserveN(now, 1)
+		if delay := reservationDelay(uuidReservation, now); delay > 0 {
+			uuidReservation.CancelAt(now)
+			originReservation.CancelAt(now)
+			return l.rejected(originSystemID, WriteLimitUUIDRate, delay)
+		}
+	}
+
+	quota.inFlight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			quota.inFlight--
+		})
+	}, "", 0
+}
+
+func (l *WriteLimiter) rejected(originSystemID string, limit string, retryAfter time.Duration) (func(), string, time.Duration) {
+	if l.observer != nil {
+		l.observer.DraftWriteLimited(originSystemID, limit)
+	}
+	return nil, limit, retryAfter
+}
+
+func (l *WriteLimiter) originQuota(originSystemID string) *originWriteQuota {
+	quota, found := l.origins[originSystemID]
+	if !found {
+		cfg, found := l.cfg.Origins[originSystemID]
+		if !found {
+			cfg = l.cfg.Default
+		}
+		quota = &originWriteQuota{limiter: newTokenBucket(cfg), maxInFlight: cfg.MaxInFlight}
+		l.origins[originSystemID] = quota
+	}
+	return quota
+}
+
+// uuidLimiter returns the rate limiter of the draft, if drafts are rate limited.
+// The limiters of the drafts that have not been written recently are dropped, as they would allow a full burst anyway.
+func (l *WriteLimiter) uuidLimiter(contentUUID string, now time.Time) *rate.Limiter {
+	if l.cfg.PerUUID.Rate <= 0 {
+		return nil
+	}
+
+	if now.Sub(l.lastSweep) > uuidLimitersSweepInterval {
+		for u, limiter := range l.uuids {
+			if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+				delete(l.uuids, u)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	limiter, found := l.uuids[contentUUID]
+	if !found {
+		limiter = newTokenBucket(l.cfg.PerUUID)
+		l.uuids[contentUUID] = limiter
+	}
+	return limiter
+}
+
+func newTokenBucket(cfg config.WriteLimitConfig) *rate.Limiter {
+	if cfg.Rate <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(cfg.Rate)))
+	}
+	return rate.NewLimiter(rate.Limit(cfg.Rate), burst)
+}
+
+func reservationDelay(reservation *rate.Reservation, now time.Time) time.Duration {
+	if !reservation.OK() {
+		return time.Second
+	}
+	return reservation.DelayFrom(now)
+}
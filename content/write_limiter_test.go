@@ -0,0 +1,164 @@
+package content
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/config"
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+	"github.com/husobee/vestigo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWriteLimitObserver struct {
+	limited []string
+}
+
+func (m *mockWriteLimitObserver) DraftWriteLimited(originSystemID string, limit string) {
+	m.limited = append(m.limited, originSystemID+" "+limit)
+}
+
+func TestWriteLimiterRateLimitsByOrigin(t *testing.T) {
+	observer := &mockWriteLimitObserver{}
+	limiter := NewWriteLimiter(config.WriteLimitsConfig{
+		Default: config.WriteLimitConfig{Rate: 0.1, Burst: 2},
+		Origins: map[string]config.WriteLimitConfig{
+			"cct": {Rate: 0.1, Burst: 3},
+		},
+	}, observer)
+
+	for i := 0; i < 2; i++ {
+		release, _, _ := limiter.acquire("spark", uuid.New().String())
+		require.NotNil(t, release)
+		release()
+	}
+	release, limit, retryAfter := limiter.acquire("spark", uuid.New().String())
+	assert.Nil(t, release)
+	assert.Equal(t, WriteLimitRate, limit)
+	assert.InDelta(t, 10, retryAfter.Seconds(), 1)
+
+	for i := 0; i < 3; i++ {
+		release, _, _ := limiter.acquire("cct", uuid.New().String())
+		require.NotNil(t, release, "origins have their own limits")
+		release()
+	}
+
+	assert.Equal(t, []string{"spark rate"}, observer.limited)
+}
+
+func TestWriteLimiterCapsInFlightWrites(t *testing.T) {
+	limiter := NewWriteLimiter(config.WriteLimitsConfig{
+		Default: config.WriteLimitConfig{MaxInFlight: 1},
+	}, nil)
+
+	release, _, _ := limiter.acquire("cct", uuid.New().String())
+	require.NotNil(t, release)
+
+	rejected, limit, _ := limiter.acquire("cct", uuid.New().String())
+	assert.Nil(t, rejected)
+	assert.Equal(t, WriteLimitInFlight, limit)
+
+	release()
+	release()
+	next, _, _ := limiter.acquire("cct", uuid.New().String())
+	assert.NotNil(t, next, "a released write frees its slot once")
+	rejected, _, _ = limiter.acquire("cct", uuid.New().String())
+	assert.Nil(t, rejected)
+}
+
+func TestWriteLimiterRateLimitsByUUID(t *testing.T) {
+	limiter := NewWriteLimiter(config.WriteLimitsConfig{
+		Default: config.WriteLimitConfig{Rate: 0.1, Burst: 2},
+		PerUUID: config.WriteLimitConfig{Rate: 0.1, Burst: 1},
+	}, nil)
+	contentUUID := uuid.New().String()
+
+	release, _, _ := limiter.acquire("cct", contentUUID)
+	require.NotNil(t, release)
+	release()
+
+	rejected, limit, _ := limiter.acquire("cct", contentUUID)
+	assert.Nil(t, rejected)
+	assert.Equal(t, WriteLimitUUIDRate, limit)
+
+	release, _, _ = limiter.acquire("cct", uuid.New().String())
+	assert.NotNil(t, release, "the write rejected by the draft limit does not use the origin quota")
+}
+
+func TestWriteNativeContentTooManyRequests(t *testing.T) {
+	contentUUID := uuid.New().String()
+	draftBody := "{\"foo\":\"bar\"}"
+
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+
+	AllowedContentTypes = map[string]struct{}{
+		contentTypeArticle: {},
+	}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(nil).Once()
+
+	limiter := NewWriteLimiter(config.WriteLimitsConfig{PerUUID: config.WriteLimitConfig{Rate: 0.5, Burst: 1}}, nil)
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"), WithWriteLimiter(limiter))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+
+	write := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", fmt.Sprintf("http://api.ft.com/drafts/nativecontent/%s", contentUUID), strings.NewReader(draftBody))
+		req.Header.Set(tidutils.TransactionIDHeader, testTID)
+		req.Header.Set(originSystemIdHeader, originIDcctTest)
+		req.Header.Set(contentTypeHeader, contentTypeArticle)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, write().Code)
+
+	w := write()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "uuid_rate limit exceeded")
+	rw.mock.AssertExpectations(t)
+}
+
+func TestWriteNativeContentIdempotentRetriesAreNotLimited(t *testing.T) {
+	contentUUID := uuid.New().String()
+	draftBody := "{\"foo\":\"bar\"}"
+
+	AllowedOriginSystemIDValues = map[string]struct{}{
+		originIDcctTest: {},
+	}
+
+	AllowedContentTypes = map[string]struct{}{
+		contentTypeArticle: {},
+	}
+
+	rw := mockDraftContentRW{}
+	rw.mock.On("Write", mock.Anything, contentUUID, draftBody, mock.Anything).Return(nil).Once()
+
+	limiter := NewWriteLimiter(config.WriteLimitsConfig{PerUUID: config.WriteLimitConfig{Rate: 0.5, Burst: 1}}, nil)
+	h := NewHandler(nil, &rw, testTimeout, logger.NewUPPLogger("test logger", "debug"),
+		WithWriteLimiter(limiter), WithIdempotencyStore(NewMemoryIdempotencyStore(time.Minute, 10)))
+	r := vestigo.NewRouter()
+	r.Put("/drafts/nativecontent/:uuid", h.WriteNativeContent)
+
+	assert.Equal(t, http.StatusOK, idempotentPut(r, contentUUID, "save-1", draftBody).Code)
+
+	w := idempotentPut(r, contentUUID, "save-1", draftBody)
+	assert.Equal(t, http.StatusOK, w.Code, "the retry is replayed rather than limited")
+	assert.Equal(t, "true", w.Header().Get(idempotentReplayedHeader))
+
+	assert.Equal(t, http.StatusTooManyRequests, idempotentPut(r, contentUUID, "save-2", draftBody).Code)
+	rw.mock.AssertExpectations(t)
+}
@@ -0,0 +1,342 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+)
+
+const (
+	lastEventIDHeader = "Last-Event-ID"
+	draftChangedEvent = "draft-changed"
+)
+
+// BrokerConfig sets the limits of a Broker.
+type BrokerConfig struct {
+	// HistorySize is the number of recent events kept for clients resuming with Last-Event-ID.
+	HistorySize int
+	// MaxConnections is the number of concurrent streams, further clients get a 503.
+	MaxConnections int
+	// MaxUUIDsPerConnection is the number of drafts a single stream can follow.
+	MaxUUIDsPerConnection int
+	// BufferSize is the number of events buffered for a stream before it is considered too slow and closed.
+	BufferSize int
+	// Heartbeat is how often a comment is sent on idle streams to keep them open.
+	Heartbeat time.Duration
+	// MaxConnectionAge is how long a stream stays open before the client is asked to reconnect.
+	MaxConnectionAge time.Duration
+}
+
+type sequencedEvent struct {
+	id string
+	// seq numbers the events written through this instance, partition and offset locate the events consumed from Kafka
+	seq       uint64
+	partition int
+	offset    int64
+	event     content.DraftEvent
+}
+
+// resumePoint is the position of a client resuming its stream with Last-Event-ID.
+type resumePoint struct {
+	// seq is set when the client resumes a stream of this instance
+	seq uint64
+	// offsets is set when the client resumes a stream fed from Kafka, by any instance
+	offsets map[int]int64
+}
+
+// missed reports whether the event came after the resume point. Without one, the client resumes a stream
+// of the drafts written through another instance, so that every recent event of this instance is new to it.
+func (p resumePoint) missed(e sequencedEvent) bool {
+	switch {
+	case p.offsets != nil:
+		offset, found := p.offsets[e.partition]
+		return e.seq == 0 && (!found || e.offset > offset)
+	case p.seq > 0:
+		return e.seq > p.seq
+	default:
+		return true
+	}
+}
+
+type subscriber struct {
+	uuids  map[string]struct{}
+	events chan sequencedEvent
+}
+
+func (s *subscriber) follows(contentUUID string) bool {
+	if len(s.uuids) == 0 {
+		return true
+	}
+	_, found := s.uuids[contentUUID]
+	return found
+}
+
+// Broker streams draft events to Server-Sent Events clients. It implements content.DraftObserver for the drafts
+// written through this instance, and TopicObserver for the draft events consumed from Kafka.
+//
+// The ids of the events consumed from Kafka are the offsets reached in every partition, e.g. 0:41,1:17,
+// so that a client can resume its stream on any instance. The ids of the other events are only known to this instance.
+type Broker struct {
+	config   BrokerConfig
+	instance string
+	log      *logger.UPPLogger
+
+	mu          sync.Mutex
+	closed      bool
+	lastSeq     uint64
+	offsets     map[int]int64
+	history     []sequencedEvent
+	subscribers map[*subscriber]struct{}
+}
+
+// TopicObserver is notified of the draft events consumed from a Kafka topic, with their partition and offset.
+type TopicObserver interface {
+	DraftConsumed(ctx context.Context, event content.DraftEvent, partition int, offset int64)
+}
+
+func NewBroker(config BrokerConfig, log *logger.UPPLogger) *Broker {
+	return &Broker{
+		config:      config,
+		instance:    strings.ReplaceAll(uuid.NewString(), "-", "")[:8],
+		log:         log,
+		offsets:     map[int]int64{},
+		subscribers: map[*subscriber]struct{}{},
+	}
+}
+
+func (b *Broker) DraftChanged(_ context.Context, event content.DraftEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastSeq++
+	b.publish(sequencedEvent{id: b.instance + "-" + strconv.FormatUint(b.lastSeq, 10), seq: b.lastSeq, event: event})
+}
+
+func (b *Broker) DraftConsumed(_ context.Context, event content.DraftEvent, partition int, offset int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.offsets[partition] = offset
+	b.publish(sequencedEvent{id: formatOffsets(b.offsets), partition: partition, offset: offset, event: event})
+}
+
+func (b *Broker) publish(e sequencedEvent) {
+	b.history = append(b.history, e)
+	if len(b.history) > b.config.HistorySize {
+		b.history = b.history[len(b.history)-b.config.HistorySize:]
+	}
+
+	for s := range b.subscribers {
+		if !s.follows(e.event.UUID) {
+			continue
+		}
+		select {
+		case s.events <- e:
+		default:
+			b.log.WithField("uuid", e.event.UUID).Warn("Draft events stream is too slow, closing it")
+			delete(b.subscribers, s)
+			close(s.events)
+		}
+	}
+}
+
+// ServeEvents streams the events of the drafts given in the uuid query parameters, or of all drafts if there are none.
+func (b *Broker) ServeEvents(w http.ResponseWriter, r *http.Request) {
+	tID := tidutils.GetTransactionIDFromRequest(r)
+	streamLog := b.log.WithField(tidutils.TransactionIDHeader, tID)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeMessage(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	uuids, err := parseUUIDs(r.URL.Query()["uuid"])
+	if err != nil {
+		writeMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(uuids) > b.config.MaxUUIDsPerConnection {
+		writeMessage(w, fmt.Sprintf("Too many uuids, at most %d can be followed", b.config.MaxUUIDsPerConnection), http.StatusBadRequest)
+		return
+	}
+
+	var resume *resumePoint
+	if header := r.Header.Get(lastEventIDHeader); header != "" {
+		if resume, err = b.parseLastEventID(header); err != nil {
+			writeMessage(w, fmt.Sprintf("Invalid %s: %v", lastEventIDHeader, header), http.StatusBadRequest)
+			return
+		}
+	}
+
+	s := &subscriber{uuids: uuids, events: make(chan sequencedEvent, b.config.BufferSize)}
+	missed, ok := b.subscribe(s, resume)
+	if !ok {
+		w.Header().Set("Retry-After", "5")
+		writeMessage(w, "Too many draft events streams", http.StatusServiceUnavailable)
+		return
+	}
+	defer b.unsubscribe(s)
+
+	// streams are expected to outlive the server write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", b.config.Heartbeat.Milliseconds())
+
+	for _, e := range missed {
+		writeEvent(w, e)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(b.config.Heartbeat)
+	defer heartbeat.Stop()
+	maxAge := time.NewTimer(b.config.MaxConnectionAge)
+	defer maxAge.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-maxAge.C:
+			streamLog.Debug("Draft events stream reached its maximum age")
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case e, open := <-s.events:
+			if !open {
+				return
+			}
+			writeEvent(w, e)
+			flusher.Flush()
+		}
+	}
+}
+
+// subscribe registers the subscriber and returns the events it missed since the resume point, if any.
+func (b *Broker) subscribe(s *subscriber, resume *resumePoint) ([]sequencedEvent, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed || len(b.subscribers) >= b.config.MaxConnections {
+		return nil, false
+	}
+	b.subscribers[s] = struct{}{}
+
+	var missed []sequencedEvent
+	if resume == nil {
+		return missed, true
+	}
+	for _, e := range b.history {
+		if resume.missed(e) && s.follows(e.event.UUID) {
+			missed = append(missed, e)
+		}
+	}
+	return missed, true
+}
+
+// Close ends the open streams and rejects new ones, clients reconnect to another instance.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for s := range b.subscribers {
+		delete(b.subscribers, s)
+		close(s.events)
+	}
+}
+
+func (b *Broker) unsubscribe(s *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, found := b.subscribers[s]; found {
+		delete(b.subscribers, s)
+		close(s.events)
+	}
+}
+
+// parseLastEventID returns where a client resumes its stream, from the id of the last event it received.
+func (b *Broker) parseLastEventID(id string) (*resumePoint, error) {
+	if instance, seq, found := strings.Cut(id, "-"); found {
+		n, err := strconv.ParseUint(seq, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if instance != b.instance {
+			return &resumePoint{}, nil
+		}
+		return &resumePoint{seq: n}, nil
+	}
+
+	offsets := map[int]int64{}
+	for _, position := range strings.Split(id, ",") {
+		partition, offset, found := strings.Cut(position, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid position %q", position)
+		}
+		p, err := strconv.Atoi(partition)
+		if err != nil {
+			return nil, err
+		}
+		if offsets[p], err = strconv.ParseInt(offset, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return &resumePoint{offsets: offsets}, nil
+}
+
+func formatOffsets(offsets map[int]int64) string {
+	partitions := make([]int, 0, len(offsets))
+	for partition := range offsets {
+		partitions = append(partitions, partition)
+	}
+	sort.Ints(partitions)
+
+	positions := make([]string, 0, len(partitions))
+	for _, partition := range partitions {
+		positions = append(positions, strconv.Itoa(partition)+":"+strconv.FormatInt(offsets[partition], 10))
+	}
+	return strings.Join(positions, ",")
+}
+
+func parseUUIDs(values []string) (map[string]struct{}, error) {
+	uuids := map[string]struct{}{}
+	for _, value := range values {
+		for _, u := range strings.Split(value, ",") {
+			if _, err := uuid.Parse(u); err != nil {
+				return nil, fmt.Errorf("Invalid content UUID: %v", u)
+			}
+			uuids[strings.ToLower(u)] = struct{}{}
+		}
+	}
+	return uuids, nil
+}
+
+func writeEvent(w http.ResponseWriter, e sequencedEvent) {
+	data, _ := json.Marshal(e.event)
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.id, draftChangedEvent, data)
+}
+
+func writeMessage(w http.ResponseWriter, errMsg string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"message": "%v"}`, errMsg)
+}
@@ -0,0 +1,233 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	followedUUID = "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	otherUUID    = "fba9884e-0756-11e8-0074-38e932af9738"
+)
+
+func newTestBroker(maxConnections int) *Broker {
+	return NewBroker(BrokerConfig{
+		HistorySize:           10,
+		MaxConnections:        maxConnections,
+		MaxUUIDsPerConnection: 2,
+		BufferSize:            10,
+		Heartbeat:             time.Minute,
+		MaxConnectionAge:      time.Minute,
+	}, logger.NewUPPLogger("test logger", "debug"))
+}
+
+// newTestServer serves the broker until the end of the test,
+// the streams opened during the test are closed first.
+func newTestServer(t *testing.T, broker *Broker) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(broker.ServeEvents))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// openStream connects to the broker and returns the lines of the stream.
+func openStream(t *testing.T, server *httptest.Server, query string, lastEventID string) (*http.Response, *bufio.Scanner) {
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/drafts/events"+query, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set(lastEventIDHeader, lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp, bufio.NewScanner(resp.Body)
+}
+
+// nextEvent returns the id and data lines of the next event on the stream.
+func nextEvent(t *testing.T, lines *bufio.Scanner) (string, string) {
+	var id, data string
+	for lines.Scan() {
+		line := lines.Text()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && data != "":
+			return id, data
+		}
+	}
+	t.Fatal("stream ended before an event was received")
+	return "", ""
+}
+
+func waitForSubscribers(t *testing.T, broker *Broker, n int) {
+	assert.Eventually(t, func() bool {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		return len(broker.subscribers) == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestBrokerStreamsFollowedDrafts(t *testing.T) {
+	broker := newTestBroker(10)
+	server := newTestServer(t, broker)
+
+	resp, lines := openStream(t, server, "?uuid="+followedUUID, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	waitForSubscribers(t, broker, 1)
+
+	broker.DraftChanged(context.TODO(), testEvent(otherUUID))
+	broker.DraftChanged(context.TODO(), testEvent(followedUUID))
+
+	id, data := nextEvent(t, lines)
+	assert.Equal(t, broker.instance+"-2", id)
+	assert.Contains(t, data, followedUUID)
+}
+
+func TestBrokerResumesFromLastEventID(t *testing.T) {
+	broker := newTestBroker(10)
+	server := newTestServer(t, broker)
+
+	broker.DraftChanged(context.TODO(), testEvent(followedUUID))
+	broker.DraftChanged(context.TODO(), testEvent(otherUUID))
+	broker.DraftChanged(context.TODO(), testEvent(followedUUID))
+
+	_, lines := openStream(t, server, "?uuid="+followedUUID, broker.instance+"-1")
+
+	id, _ := nextEvent(t, lines)
+	assert.Equal(t, broker.instance+"-3", id)
+}
+
+func TestBrokerResumesStreamOfAnotherInstance(t *testing.T) {
+	broker := newTestBroker(10)
+	server := newTestServer(t, broker)
+
+	broker.DraftChanged(context.TODO(), testEvent(followedUUID))
+
+	_, lines := openStream(t, server, "?uuid="+followedUUID, "0a1b2c3d-7")
+
+	id, _ := nextEvent(t, lines)
+	assert.Equal(t, broker.instance+"-1", id, "the drafts written through this instance were not streamed by the other one")
+}
+
+func TestBrokerResumesFromKafkaOffsets(t *testing.T) {
+	broker := newTestBroker(10)
+	server := newTestServer(t, broker)
+
+	broker.DraftConsumed(context.TODO(), testEvent(followedUUID), 0, 41)
+	broker.DraftConsumed(context.TODO(), testEvent(followedUUID), 1, 17)
+	broker.DraftConsumed(context.TODO(), testEvent(followedUUID), 0, 42)
+	broker.DraftConsumed(context.TODO(), testEvent(followedUUID), 2, 5)
+
+	// another instance consumed partition 1 before partition 0, and streamed up to 1:17
+	_, lines := openStream(t, server, "?uuid="+followedUUID, "1:17")
+
+	id, _ := nextEvent(t, lines)
+	assert.Equal(t, "0:41", id)
+	id, _ = nextEvent(t, lines)
+	assert.Equal(t, "0:42,1:17", id)
+	id, _ = nextEvent(t, lines)
+	assert.Equal(t, "0:42,1:17,2:5", id)
+
+	_, lines = openStream(t, server, "?uuid="+followedUUID, "0:42,1:17")
+
+	id, _ = nextEvent(t, lines)
+	assert.Equal(t, "0:42,1:17,2:5", id)
+}
+
+func TestBrokerStreamsAllDraftsWithoutUUIDs(t *testing.T) {
+	broker := newTestBroker(10)
+	server := newTestServer(t, broker)
+
+	_, lines := openStream(t, server, "", "")
+	waitForSubscribers(t, broker, 1)
+
+	broker.DraftChanged(context.TODO(), testEvent(otherUUID))
+
+	_, data := nextEvent(t, lines)
+	assert.Contains(t, data, otherUUID)
+}
+
+func TestBrokerRejectsTooManyConnections(t *testing.T) {
+	broker := newTestBroker(1)
+	server := newTestServer(t, broker)
+
+	openStream(t, server, "", "")
+	waitForSubscribers(t, broker, 1)
+
+	resp, _ := openStream(t, server, "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+}
+
+func TestBrokerRejectsInvalidRequests(t *testing.T) {
+	broker := newTestBroker(10)
+
+	tests := map[string]struct {
+		query       string
+		lastEventID string
+	}{
+		"invalid uuid":          {query: "?uuid=not-a-uuid"},
+		"too many uuids":        {query: "?uuid=" + followedUUID + "," + otherUUID + "&uuid=0e8f7a2c-2a5e-11e8-a8c9-8f2d4a5b2c3d"},
+		"invalid last event id": {lastEventID: "abc"},
+		"invalid offsets":       {lastEventID: "0:41,1"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/drafts/events"+test.query, nil)
+			if test.lastEventID != "" {
+				req.Header.Set(lastEventIDHeader, test.lastEventID)
+			}
+			w := httptest.NewRecorder()
+
+			broker.ServeEvents(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestBrokerClosesSlowStreams(t *testing.T) {
+	broker := newTestBroker(10)
+	s := &subscriber{uuids: map[string]struct{}{}, events: make(chan sequencedEvent, 1)}
+	_, ok := broker.subscribe(s, nil)
+	require.True(t, ok)
+
+	broker.DraftChanged(context.TODO(), testEvent(followedUUID))
+	broker.DraftChanged(context.TODO(), testEvent(followedUUID))
+
+	assert.Len(t, broker.subscribers, 0)
+	<-s.events
+	_, open := <-s.events
+	assert.False(t, open)
+}
+
+func TestBrokerCloseEndsStreams(t *testing.T) {
+	broker := newTestBroker(10)
+	server := newTestServer(t, broker)
+
+	_, lines := openStream(t, server, "", "")
+	waitForSubscribers(t, broker, 1)
+
+	broker.Close()
+
+	for lines.Scan() {
+	}
+	assert.NoError(t, lines.Err())
+
+	resp, _ := openStream(t, server, "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
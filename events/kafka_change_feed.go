@@ -0,0 +1,164 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/go-logger/v2"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	minReadBackoff = 100 * time.Millisecond
+	maxReadBackoff = 30 * time.Second
+)
+
+// KafkaChangeFeed consumes the draft events published by every instance of the service,
+// so that draft events streams also see the drafts written through the other instances.
+// It reads every partition of the topic directly, without a consumer group, as every instance needs all the events
+// and none of them has offsets to resume from.
+type KafkaChangeFeed struct {
+	brokers  []string
+	topic    string
+	observer TopicObserver
+	log      *logger.UPPLogger
+
+	mu      sync.Mutex
+	readers []*kafka.Reader
+	closed  bool
+}
+
+// NewKafkaChangeFeed returns a change feed notifying the observer of the events published to the topic, and of their position.
+// Only the events published after the feed started are consumed, and partitions added to the topic later
+// are only consumed after a restart.
+func NewKafkaChangeFeed(brokers []string, topic string, observer TopicObserver, log *logger.UPPLogger) *KafkaChangeFeed {
+	return &KafkaChangeFeed{
+		brokers:  brokers,
+		topic:    topic,
+		observer: observer,
+		log:      log,
+	}
+}
+
+// Run consumes the topic until the context is cancelled.
+// Errors are retried after a backoff, doubling up to 30s while they persist.
+func (f *KafkaChangeFeed) Run(ctx context.Context) {
+	var partitions []kafka.Partition
+	backoff := minReadBackoff
+	for {
+		var err error
+		if partitions, err = f.lookupPartitions(ctx); err == nil {
+			break
+		}
+		f.log.WithError(err).WithField("retryIn", backoff.String()).Error("Error looking up the partitions of the draft events topic")
+		if !wait(ctx, backoff) {
+			return
+		}
+		backoff = nextBackoff(backoff)
+	}
+
+	var wg sync.WaitGroup
+	for _, partition := range partitions {
+		reader, ok := f.newReader(partition.ID)
+		if !ok {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.consume(ctx, reader)
+		}()
+	}
+	wg.Wait()
+}
+
+func (f *KafkaChangeFeed) lookupPartitions(ctx context.Context) ([]kafka.Partition, error) {
+	err := errors.New("no kafka broker")
+	for _, broker := range f.brokers {
+		var partitions []kafka.Partition
+		if partitions, err = kafka.LookupPartitions(ctx, "tcp", broker, f.topic); err == nil {
+			return partitions, nil
+		}
+	}
+	return nil, err
+}
+
+// newReader returns a reader of the partition starting at its last offset, unless the feed is closed.
+func (f *KafkaChangeFeed) newReader(partition int) (*kafka.Reader, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil, false
+	}
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   f.brokers,
+		Topic:     f.topic,
+		Partition: partition,
+	})
+	reader.SetOffset(kafka.LastOffset)
+	f.readers = append(f.readers, reader)
+	return reader, true
+}
+
+func (f *KafkaChangeFeed) consume(ctx context.Context, reader *kafka.Reader) {
+	backoff := minReadBackoff
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return
+			}
+			f.log.WithError(err).WithField("retryIn", backoff.String()).Error("Error reading draft changed events")
+			if !wait(ctx, backoff) {
+				return
+			}
+			backoff = nextBackoff(backoff)
+			continue
+		}
+		backoff = minReadBackoff
+
+		var event content.DraftEvent
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			f.log.WithError(err).WithField("partition", msg.Partition).WithField("offset", msg.Offset).Warn("Skipping malformed draft changed event")
+			continue
+		}
+		f.observer.DraftConsumed(ctx, event, msg.Partition, msg.Offset)
+	}
+}
+
+func (f *KafkaChangeFeed) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	var errs []error
+	for _, reader := range f.readers {
+		errs = append(errs, reader.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// wait returns after the backoff, or false if the context is cancelled first.
+func wait(ctx context.Context, backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func nextBackoff(backoff time.Duration) time.Duration {
+	if backoff *= 2; backoff > maxReadBackoff {
+		return maxReadBackoff
+	}
+	return backoff
+}
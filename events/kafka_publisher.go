@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/segmentio/kafka-go"
+)
+
+const batchTimeout = 5 * time.Millisecond
+
+// KafkaPublisher publishes draft events as JSON messages keyed on the content UUID,
+// so that the events of a draft, which the Outbox publishes in order, are consumed in order.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaPublisher(brokers []string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// the Outbox publishes one event at a time, so it is written straight away rather than batched
+			BatchSize:    1,
+			BatchTimeout: batchTimeout,
+			// retries are handled by the Outbox
+			MaxAttempts: 1,
+		},
+	}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, event content.DraftEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(event.UUID),
+		Value: value,
+		Headers: []kafka.Header{
+			{Key: tidutils.TransactionIDHeader, Value: []byte(event.TransactionID)},
+			{Key: "Content-Type", Value: []byte("application/json")},
+		},
+	})
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
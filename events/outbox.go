@@ -0,0 +1,247 @@
+package events
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+)
+
+const (
+	publishTimeout = 10 * time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// Outbox stores draft events in its OutboxStore, then publishes them in the background.
+// Failed events are retried with an exponential backoff without holding up the events of the other drafts,
+// while the later events of their draft wait for them, and stay in the store until they are published, so that transient publisher errors, and restarts
+// when the store is persistent, do not lose events. It implements content.DraftObserver.
+type Outbox struct {
+	publisher  Publisher
+	store      OutboxStore
+	size       int
+	maxRetries int
+	backoff    time.Duration
+	log        *logger.UPPLogger
+
+	mu      sync.Mutex
+	pending map[uint64]*outboxEntry
+	// loaded is the sequence number of the last stored event loaded in pending
+	loaded uint64
+	// overflow is set while the store holds events not loaded in pending yet
+	overflow bool
+	closed   bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wake   chan struct{}
+	done   chan struct{}
+}
+
+type outboxEntry struct {
+	PendingEvent
+	attempts    int
+	nextAttempt time.Time
+}
+
+// NewOutbox starts publishing the events of the store, including those left by a previous run.
+// Up to size events are scheduled at a time, the others wait in the store. After a failed attempt,
+// an event is retried after backoff, then twice as long every time; after maxRetries it keeps being retried at the longest backoff.
+func NewOutbox(publisher Publisher, store OutboxStore, size int, maxRetries int, backoff time.Duration, log *logger.UPPLogger) *Outbox {
+	ctx, cancel := context.WithCancel(context.Background())
+	o := &Outbox{
+		publisher:  publisher,
+		store:      store,
+		size:       size,
+		maxRetries: maxRetries,
+		backoff:    backoff,
+		log:        log,
+		pending:    map[uint64]*outboxEntry{},
+		overflow:   true,
+		ctx:        ctx,
+		cancel:     cancel,
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+
+	go o.run()
+	return o
+}
+
+func (o *Outbox) DraftChanged(_ context.Context, event content.DraftEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	eventLog := o.log.WithField(tidutils.TransactionIDHeader, event.TransactionID).WithField("uuid", event.UUID)
+	if o.closed {
+		eventLog.Error("Draft event outbox is closed, dropping draft event")
+		return
+	}
+
+	seq, err := o.store.Add(event)
+	if err != nil {
+		eventLog.WithError(err).Error("Unable to store draft event, dropping it")
+		return
+	}
+
+	if o.overflow || len(o.pending) >= o.size {
+		o.overflow = true
+		eventLog.Warn("Draft event outbox is full, the draft event is published once it drains")
+		return
+	}
+	o.pending[seq] = &outboxEntry{PendingEvent: PendingEvent{seq, event}, nextAttempt: time.Now()}
+	o.loaded = seq
+	o.signal()
+}
+
+// Close stops accepting events and publishes those that are due until ctx is done.
+// The events left are kept in the store.
+func (o *Outbox) Close(ctx context.Context) {
+	o.mu.Lock()
+	o.closed = true
+	o.mu.Unlock()
+	o.signal()
+
+	select {
+	case <-o.done:
+	case <-ctx.Done():
+		o.cancel()
+		<-o.done
+	}
+	o.cancel()
+
+	o.mu.Lock()
+	left := len(o.pending)
+	overflow := o.overflow
+	o.mu.Unlock()
+	if left > 0 || overflow {
+		o.log.WithField("events", left).Warn("Draft events are left unpublished in the outbox store")
+	}
+
+	if closer, ok := o.store.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+func (o *Outbox) signal() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Outbox) run() {
+	defer close(o.done)
+
+	for {
+		entry, wait, closed := o.next()
+		if entry != nil {
+			o.publish(entry)
+			continue
+		}
+		if closed {
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-o.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// next returns the event due the earliest, or how long to wait for one.
+func (o *Outbox) next() (*outboxEntry, time.Duration, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.ctx.Err() != nil {
+		return nil, 0, true
+	}
+	if o.overflow && len(o.pending) < o.size {
+		o.load()
+	}
+
+	// the events of a draft are published in order: an event waits for the earlier events of its draft
+	oldest := map[string]uint64{}
+	for _, entry := range o.pending {
+		if seq, found := oldest[entry.Event.UUID]; !found || entry.Seq < seq {
+			oldest[entry.Event.UUID] = entry.Seq
+		}
+	}
+
+	var next *outboxEntry
+	for _, entry := range o.pending {
+		if oldest[entry.Event.UUID] != entry.Seq {
+			continue
+		}
+		if next == nil || entry.nextAttempt.Before(next.nextAttempt) ||
+			(entry.nextAttempt.Equal(next.nextAttempt) && entry.Seq < next.Seq) {
+			next = entry
+		}
+	}
+
+	if next == nil {
+		return nil, maxBackoff, o.closed
+	}
+	if wait := time.Until(next.nextAttempt); wait > 0 {
+		return nil, wait, o.closed
+	}
+	return next, 0, false
+}
+
+func (o *Outbox) load() {
+	stored, err := o.store.Pending(o.loaded, o.size-len(o.pending))
+	if err != nil {
+		o.log.WithError(err).Error("Unable to load draft events from the outbox store")
+		return
+	}
+
+	now := time.Now()
+	for _, event := range stored {
+		o.pending[event.Seq] = &outboxEntry{PendingEvent: event, nextAttempt: now}
+		o.loaded = event.Seq
+	}
+	o.overflow = len(o.pending) >= o.size
+}
+
+func (o *Outbox) publish(entry *outboxEntry) {
+	eventLog := o.log.WithField(tidutils.TransactionIDHeader, entry.Event.TransactionID).WithField("uuid", entry.Event.UUID)
+
+	ctx, cancel := context.WithTimeout(o.ctx, publishTimeout)
+	err := o.publisher.Publish(ctx, entry.Event)
+	cancel()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err == nil {
+		delete(o.pending, entry.Seq)
+		if err = o.store.Remove(entry.Seq); err != nil {
+			eventLog.WithError(err).Error("Unable to remove published draft event from the outbox store")
+		}
+		return
+	}
+
+	entry.attempts++
+	backoff := o.backoff
+	for i := 1; i < entry.attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	entry.nextAttempt = time.Now().Add(backoff)
+
+	if entry.attempts > o.maxRetries {
+		eventLog.WithError(err).WithField("attempt", entry.attempts).Error("Unable to publish draft event, it stays in the outbox and is retried")
+		return
+	}
+	eventLog.WithError(err).WithField("attempt", entry.attempts).Warn("Unable to publish draft event, retrying")
+}
@@ -0,0 +1,149 @@
+package events
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	bolt "go.etcd.io/bbolt"
+)
+
+var outboxBucket = []byte("pending")
+
+// PendingEvent is a draft event stored in the outbox until it is published.
+type PendingEvent struct {
+	Seq   uint64
+	Event content.DraftEvent
+}
+
+// OutboxStore keeps the draft events of the Outbox until they are published.
+type OutboxStore interface {
+	// Add stores the event and returns its sequence number, greater than those of the events added before it.
+	Add(event content.DraftEvent) (uint64, error)
+	// Remove deletes a published event.
+	Remove(seq uint64) error
+	// Pending returns, in sequence order, up to max stored events whose sequence number is greater than after.
+	Pending(after uint64, max int) ([]PendingEvent, error)
+}
+
+type memoryOutboxStore struct {
+	mu     sync.Mutex
+	seq    uint64
+	events map[uint64]content.DraftEvent
+}
+
+// NewMemoryOutboxStore returns an OutboxStore keeping the events in memory, so that they are lost on restart.
+func NewMemoryOutboxStore() OutboxStore {
+	return &memoryOutboxStore{events: map[uint64]content.DraftEvent{}}
+}
+
+func (s *memoryOutboxStore) Add(event content.DraftEvent) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	s.events[s.seq] = event
+	return s.seq, nil
+}
+
+func (s *memoryOutboxStore) Remove(seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.events, seq)
+	return nil
+}
+
+func (s *memoryOutboxStore) Pending(after uint64, max int) ([]PendingEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []PendingEvent
+	for seq, event := range s.events {
+		if seq > after {
+			pending = append(pending, PendingEvent{seq, event})
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Seq < pending[j].Seq })
+	if len(pending) > max {
+		pending = pending[:max]
+	}
+	return pending, nil
+}
+
+// BoltOutboxStore keeps the events in an embedded database file, so that they are published after a restart.
+type BoltOutboxStore struct {
+	db *bolt.DB
+}
+
+// NewBoltOutboxStore opens, or creates, the outbox database file.
+func NewBoltOutboxStore(path string) (*BoltOutboxStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(outboxBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltOutboxStore{db}, nil
+}
+
+func (s *BoltOutboxStore) Add(event content.DraftEvent) (uint64, error) {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	var seq uint64
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(outboxBucket)
+		seq, err = bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		return bucket.Put(outboxKey(seq), value)
+	})
+	return seq, err
+}
+
+func (s *BoltOutboxStore) Remove(seq uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(outboxBucket).Delete(outboxKey(seq))
+	})
+}
+
+func (s *BoltOutboxStore) Pending(after uint64, max int) ([]PendingEvent, error) {
+	var pending []PendingEvent
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(outboxBucket).Cursor()
+		for k, v := c.Seek(outboxKey(after + 1)); k != nil && len(pending) < max; k, v = c.Next() {
+			var event content.DraftEvent
+			if err := json.Unmarshal(v, &event); err != nil {
+				return err
+			}
+			pending = append(pending, PendingEvent{binary.BigEndian.Uint64(k), event})
+		}
+		return nil
+	})
+	return pending, err
+}
+
+func (s *BoltOutboxStore) Close() error {
+	return s.db.Close()
+}
+
+func outboxKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
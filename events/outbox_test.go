@@ -0,0 +1,214 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/go-logger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPublisher struct {
+	mock.Mock
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, event content.DraftEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func newTestOutbox(publisher Publisher, store OutboxStore, size int, maxRetries int) *Outbox {
+	return NewOutbox(publisher, store, size, maxRetries, time.Millisecond, logger.NewUPPLogger("test logger", "debug"))
+}
+
+func closeWithin(outbox *Outbox, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	outbox.Close(ctx)
+}
+
+func TestOutboxDeliversEvents(t *testing.T) {
+	publisher := NewMemoryPublisher()
+	store := NewMemoryOutboxStore()
+	outbox := newTestOutbox(publisher, store, 10, 3)
+
+	outbox.DraftChanged(context.TODO(), testEvent("83a201c6-60cd-11e7-91a7-502f7ee26895"))
+	outbox.DraftChanged(context.TODO(), testEvent("fba9884e-0756-11e8-0074-38e932af9738"))
+	closeWithin(outbox, time.Second)
+
+	events := publisher.Events()
+	assert.Len(t, events, 2)
+	assert.Equal(t, "83a201c6-60cd-11e7-91a7-502f7ee26895", events[0].UUID)
+	assert.Equal(t, "fba9884e-0756-11e8-0074-38e932af9738", events[1].UUID)
+
+	pending, err := store.Pending(0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending, "published events are removed from the store")
+}
+
+func TestOutboxRetriesTransientErrors(t *testing.T) {
+	event := testEvent("83a201c6-60cd-11e7-91a7-502f7ee26895")
+
+	publisher := &mockPublisher{}
+	publisher.On("Publish", event).Return(errors.New("broker not available")).Twice()
+	publisher.On("Publish", event).Return(nil).Once()
+
+	store := NewMemoryOutboxStore()
+	outbox := newTestOutbox(publisher, store, 10, 3)
+	outbox.DraftChanged(context.TODO(), event)
+	assert.Eventually(t, func() bool {
+		pending, _ := store.Pending(0, 10)
+		return len(pending) == 0
+	}, time.Second, time.Millisecond)
+	closeWithin(outbox, time.Second)
+
+	publisher.AssertExpectations(t)
+	publisher.AssertNumberOfCalls(t, "Publish", 3)
+}
+
+func TestOutboxFailingEventsDoNotHoldUpOthers(t *testing.T) {
+	failing := testEvent("83a201c6-60cd-11e7-91a7-502f7ee26895")
+	other := testEvent("fba9884e-0756-11e8-0074-38e932af9738")
+
+	publisher := &mockPublisher{}
+	publisher.On("Publish", failing).Return(errors.New("message too large"))
+	publisher.On("Publish", other).Return(nil).Once()
+
+	store := NewMemoryOutboxStore()
+	outbox := NewOutbox(publisher, store, 10, 0, time.Hour, logger.NewUPPLogger("test logger", "debug"))
+	outbox.DraftChanged(context.TODO(), failing)
+	outbox.DraftChanged(context.TODO(), other)
+	assert.Eventually(t, func() bool {
+		pending, _ := store.Pending(0, 10)
+		return len(pending) == 1
+	}, time.Second, time.Millisecond)
+	closeWithin(outbox, 10*time.Millisecond)
+
+	publisher.AssertNumberOfCalls(t, "Publish", 2)
+	pending, err := store.Pending(0, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, failing, pending[0].Event, "events that still fail stay in the store")
+}
+
+func TestOutboxKeepsTheOrderOfTheEventsOfADraft(t *testing.T) {
+	first := testEvent("83a201c6-60cd-11e7-91a7-502f7ee26895")
+	second := first
+	second.TransactionID = "tid_second"
+
+	publisher := &mockPublisher{}
+	publisher.On("Publish", first).Return(errors.New("broker not available")).Once()
+	publisher.On("Publish", first).Return(nil).Once()
+	publisher.On("Publish", second).Return(nil).Once()
+
+	store := NewMemoryOutboxStore()
+	outbox := newTestOutbox(publisher, store, 10, 3)
+	outbox.DraftChanged(context.TODO(), first)
+	outbox.DraftChanged(context.TODO(), second)
+	assert.Eventually(t, func() bool {
+		pending, _ := store.Pending(0, 10)
+		return len(pending) == 0
+	}, time.Second, time.Millisecond)
+	closeWithin(outbox, time.Second)
+
+	publisher.AssertExpectations(t)
+	require.Len(t, publisher.Calls, 3)
+	assert.Equal(t, first, publisher.Calls[1].Arguments.Get(0), "the retried event is published before the next event of its draft")
+	assert.Equal(t, second, publisher.Calls[2].Arguments.Get(0))
+}
+
+func TestOutboxDrainsTheStoreOnceFull(t *testing.T) {
+	publisher := NewMemoryPublisher()
+	store := NewMemoryOutboxStore()
+	blocked := &blockingPublisher{Publisher: publisher, release: make(chan struct{})}
+	outbox := newTestOutbox(blocked, store, 1, 0)
+
+	for i := 0; i < 5; i++ {
+		outbox.DraftChanged(context.TODO(), testEvent(fmt.Sprintf("83a201c6-60cd-11e7-91a7-502f7ee2689%d", i)))
+	}
+	close(blocked.release)
+	assert.Eventually(t, func() bool {
+		return len(publisher.Events()) == 5
+	}, time.Second, time.Millisecond)
+	closeWithin(outbox, time.Second)
+
+	for i, event := range publisher.Events() {
+		assert.Equal(t, fmt.Sprintf("83a201c6-60cd-11e7-91a7-502f7ee2689%d", i), event.UUID, "events are published in order")
+	}
+}
+
+func TestOutboxPublishesEventsLeftByAPreviousRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.db")
+	store, err := NewBoltOutboxStore(path)
+	require.NoError(t, err)
+
+	failing := &mockPublisher{}
+	failing.On("Publish", mock.Anything).Return(errors.New("broker not available"))
+	outbox := NewOutbox(failing, store, 10, 0, time.Hour, logger.NewUPPLogger("test logger", "debug"))
+	outbox.DraftChanged(context.TODO(), testEvent("83a201c6-60cd-11e7-91a7-502f7ee26895"))
+	closeWithin(outbox, 10*time.Millisecond)
+
+	store, err = NewBoltOutboxStore(path)
+	require.NoError(t, err)
+	publisher := NewMemoryPublisher()
+	outbox = newTestOutbox(publisher, store, 10, 0)
+	assert.Eventually(t, func() bool {
+		return len(publisher.Events()) == 1
+	}, time.Second, time.Millisecond)
+	closeWithin(outbox, time.Second)
+
+	assert.Equal(t, "83a201c6-60cd-11e7-91a7-502f7ee26895", publisher.Events()[0].UUID)
+}
+
+func TestOutboxCloseHasADeadline(t *testing.T) {
+	publisher := &blockingPublisher{Publisher: NewMemoryPublisher(), release: make(chan struct{})}
+	outbox := newTestOutbox(publisher, NewMemoryOutboxStore(), 10, 0)
+	outbox.DraftChanged(context.TODO(), testEvent("83a201c6-60cd-11e7-91a7-502f7ee26895"))
+
+	start := time.Now()
+	closeWithin(outbox, 20*time.Millisecond)
+	assert.Less(t, time.Since(start), publishTimeout)
+}
+
+func TestOutboxDropsEventsAfterClose(t *testing.T) {
+	publisher := NewMemoryPublisher()
+	outbox := newTestOutbox(publisher, NewMemoryOutboxStore(), 10, 0)
+	closeWithin(outbox, time.Second)
+
+	outbox.DraftChanged(context.TODO(), testEvent("83a201c6-60cd-11e7-91a7-502f7ee26895"))
+
+	assert.Empty(t, publisher.Events())
+}
+
+// blockingPublisher waits for release, or for its context to be done, before publishing.
+type blockingPublisher struct {
+	Publisher
+	release chan struct{}
+}
+
+func (p *blockingPublisher) Publish(ctx context.Context, event content.DraftEvent) error {
+	select {
+	case <-p.release:
+		return p.Publisher.Publish(ctx, event)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func testEvent(contentUUID string) content.DraftEvent {
+	return content.DraftEvent{
+		UUID:           contentUUID,
+		ContentType:    "application/vnd.ft-upp-article+json",
+		OriginSystemID: "cct",
+		TransactionID:  "test_tid",
+		WriteReference: "test_tid",
+		Timestamp:      time.Date(2018, 2, 21, 14, 25, 0, 0, time.UTC),
+	}
+}
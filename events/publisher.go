@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+)
+
+// Publisher sends draft events to downstream services.
+type Publisher interface {
+	Publish(ctx context.Context, event content.DraftEvent) error
+}
+
+type logPublisher struct {
+	log *logger.UPPLogger
+}
+
+// NewLogPublisher returns a Publisher that only logs the events, for local runs.
+func NewLogPublisher(log *logger.UPPLogger) Publisher {
+	return &logPublisher{log}
+}
+
+func (p *logPublisher) Publish(_ context.Context, event content.DraftEvent) error {
+	p.log.WithField(tidutils.TransactionIDHeader, event.TransactionID).
+		WithField("uuid", event.UUID).
+		WithField("contentType", event.ContentType).
+		WithField("originSystemId", event.OriginSystemID).
+		WithField("writeReference", event.WriteReference).
+		Info("draft changed")
+	return nil
+}
+
+// MemoryPublisher keeps the published events in memory, for tests.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []content.DraftEvent
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(_ context.Context, event content.DraftEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+	return nil
+}
+
+// Events returns the events published so far.
+func (p *MemoryPublisher) Events() []content.DraftEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]content.DraftEvent(nil), p.events...)
+}
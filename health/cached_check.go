@@ -0,0 +1,146 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/service-status-go/gtg"
+)
+
+const checkTimeout = 10 * time.Second
+
+type checkResult struct {
+	output    string
+	err       error
+	checkedAt time.Time
+}
+
+// cachedCheck keeps the last result of a check, so that health and GTG requests do not call the dependencies.
+// Until the check is cached, every request runs it.
+type cachedCheck struct {
+	checker func(ctx context.Context) (string, error)
+	timeout time.Duration
+
+	mu         sync.Mutex
+	cached     bool
+	staleAfter time.Duration
+	inFlight   bool
+	result     checkResult
+}
+
+func newCachedCheck(checker func(ctx context.Context) (string, error)) *cachedCheck {
+	return &cachedCheck{checker: checker, timeout: checkTimeout}
+}
+
+// refresh runs the check, unless the previous run is still waiting for its dependency.
+// A run is given the check timeout: its context is then cancelled and it is reported as timed out,
+// but the check is not run again until the checker has returned, so that a hung dependency does not pile up runs.
+func (c *cachedCheck) refresh() checkResult {
+	c.mu.Lock()
+	if c.inFlight {
+		result := c.result
+		c.mu.Unlock()
+		if result.checkedAt.IsZero() {
+			return checkResult{err: errors.New("not checked yet"), checkedAt: time.Now()}
+		}
+		return result
+	}
+	c.inFlight = true
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	done := make(chan checkResult, 1)
+	go func() {
+		output, err := c.checker(ctx)
+
+		c.mu.Lock()
+		c.inFlight = false
+		c.mu.Unlock()
+		done <- checkResult{output, err, time.Now()}
+	}()
+
+	var result checkResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = checkResult{err: fmt.Errorf("timed out after %v", c.timeout), checkedAt: time.Now()}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.result = result
+	return result
+}
+
+// last returns the cached result, or runs the check if there is none.
+// A result older than the staleness threshold is reported as an error,
+// as the dependency state is no longer known.
+func (c *cachedCheck) last() checkResult {
+	c.mu.Lock()
+	result, cached, staleAfter := c.result, c.cached, c.staleAfter
+	c.mu.Unlock()
+
+	if !cached {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+
+		output, err := c.checker(ctx)
+		return checkResult{output, err, time.Now()}
+	}
+	if result.checkedAt.IsZero() {
+		return c.refresh()
+	}
+
+	if age := time.Since(result.checkedAt); staleAfter > 0 && age > staleAfter {
+		return checkResult{
+			err:       fmt.Errorf("stale check result, last checked %v ago: %v", age.Round(time.Second), describe(result)),
+			checkedAt: result.checkedAt,
+		}
+	}
+	return result
+}
+
+func (c *cachedCheck) cache(staleAfter time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cached = true
+	c.staleAfter = staleAfter
+}
+
+// healthCheck is the health check Checker, reporting the age of cached results.
+func (c *cachedCheck) healthCheck() (string, error) {
+	result := c.last()
+	c.mu.Lock()
+	cached := c.cached
+	c.mu.Unlock()
+	if !cached {
+		return result.output, result.err
+	}
+
+	age := time.Since(result.checkedAt).Round(time.Second)
+	if result.err != nil {
+		return "", fmt.Errorf("%w (checked %v ago)", result.err, age)
+	}
+	return fmt.Sprintf("%s (checked %v ago)", result.output, age), nil
+}
+
+func (c *cachedCheck) gtgStatus() gtg.Status {
+	result := c.last()
+	if result.err != nil {
+		return gtg.Status{GoodToGo: false, Message: result.err.Error()}
+	}
+	return gtg.Status{GoodToGo: true}
+}
+
+func describe(result checkResult) string {
+	if result.err != nil {
+		return result.err.Error()
+	}
+	return result.output
+}
@@ -1,11 +1,17 @@
 package health
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Financial-Times/draft-content-api/config"
+	"github.com/Financial-Times/draft-content-api/platform"
 	health "github.com/Financial-Times/go-fthealth/v1_1"
 	"github.com/Financial-Times/service-status-go/gtg"
 )
@@ -15,25 +21,51 @@ type ExternalService interface {
 	GTG() error
 }
 
+// contextService is implemented by the external services whose good-to-go call can be cancelled.
+type contextService interface {
+	GTGContext(ctx context.Context) error
+}
+
+const (
+	critical = "critical"
+	optional = "optional"
+)
+
 type Service struct {
 	health.HealthCheck
 	uppContentAPI  ExternalService
 	draftContentRW ExternalService
+	cachedChecks   []*cachedCheck
+	criticalChecks []*cachedCheck
+	// validatorChecks are the checks of the validators by endpoint, and contentTypes the validator endpoints by content type
+	validatorChecks map[string]*cachedCheck
+	contentTypes    map[string]string
+	// bulkheads are the validators limiting their concurrent requests by endpoint, with their rejections at the previous check
+	bulkheads      map[string]bulkheadReporter
+	bulkheadsMutex sync.Mutex
+	lastRejected   map[string]uint64
+}
+
+// bulkheadReporter is implemented by the services limiting their concurrent requests.
+type bulkheadReporter interface {
+	BulkheadStats() (platform.BulkheadStats, bool)
 }
 
 func NewHealthService(appSystemCode string, appName string, appDescription string,
 	draftContent ExternalService, capi ExternalService, hcConfig *config.Config, services []ExternalService) (*Service, error) {
 	service := &Service{
-		draftContentRW: draftContent,
-		uppContentAPI:  capi,
+		draftContentRW:  draftContent,
+		uppContentAPI:   capi,
+		validatorChecks: map[string]*cachedCheck{},
+		contentTypes:    map[string]string{},
+		bulkheads:       map[string]bulkheadReporter{},
+		lastRejected:    map[string]uint64{},
 	}
 	service.SystemCode = appSystemCode
 	service.Name = appName
 	service.Description = appDescription
-	service.Checks = []health.Check{
-		service.draftContentRWCheck(),
-		service.contentAPICheck(),
-	}
+	service.addCheck(service.draftContentRWCheck(), externalServiceChecker(service.draftContentRW, "Draft content RW"), true)
+	service.addCheck(service.contentAPICheck(), externalServiceChecker(service.uppContentAPI, "Content API"), true)
 
 	for endpoint, cfg := range hcConfig.HealthChecks {
 		externalService, err := findService(endpoint, services)
@@ -41,6 +73,15 @@ func NewHealthService(appSystemCode string, appName string, appDescription strin
 			return nil, err
 		}
 
+		isCritical := true
+		switch cfg.Criticality {
+		case "", critical:
+		case optional:
+			isCritical = false
+		default:
+			return nil, fmt.Errorf("unknown criticality %q of the %v health check", cfg.Criticality, endpoint)
+		}
+
 		c := health.Check{
 			ID:               cfg.ID,
 			BusinessImpact:   cfg.BusinessImpact,
@@ -48,14 +89,70 @@ func NewHealthService(appSystemCode string, appName string, appDescription strin
 			PanicGuide:       cfg.PanicGuide,
 			Severity:         cfg.Severity,
 			TechnicalSummary: fmt.Sprintf(cfg.TechnicalSummary, endpoint),
-			Checker:          externalServiceChecker(externalService, cfg.CheckerName),
 		}
-		service.Checks = append(service.Checks, c)
+		service.validatorChecks[endpoint] = service.addCheck(c, externalServiceChecker(externalService, cfg.CheckerName), isCritical)
+	}
+
+	for contentType, cfg := range hcConfig.ContentTypes {
+		service.contentTypes[contentType] = cfg.Endpoint
+	}
+	if len(service.contentTypes) > 0 {
+		service.Checks = append(service.Checks, service.capabilitiesCheck())
+	}
+
+	for _, s := range services {
+		if reporter, ok := s.(bulkheadReporter); ok {
+			if _, limited := reporter.BulkheadStats(); limited {
+				service.bulkheads[s.Endpoint()] = reporter
+			}
+		}
+	}
+	if len(service.bulkheads) > 0 {
+		service.Checks = append(service.Checks, service.saturationCheck())
 	}
 
 	return service, nil
 }
 
+// addCheck serves the check from the cached result of its checker. Only failing critical checks fail the GTG.
+func (service *Service) addCheck(c health.Check, checker func(ctx context.Context) (string, error), isCritical bool) *cachedCheck {
+	cached := newCachedCheck(checker)
+	c.Checker = cached.healthCheck
+	service.Checks = append(service.Checks, c)
+
+	service.cachedChecks = append(service.cachedChecks, cached)
+	if isCritical {
+		service.criticalChecks = append(service.criticalChecks, cached)
+	}
+	return cached
+}
+
+// Start runs the checks now and then every interval until the context is done,
+// after which health and GTG requests are served from the last results.
+// A result older than staleAfter fails its check, as the dependency state is no longer known.
+func (service *Service) Start(ctx context.Context, interval time.Duration, staleAfter time.Duration) {
+	for _, c := range service.cachedChecks {
+		c.cache(staleAfter)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for _, c := range service.cachedChecks {
+				go c.refresh()
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
 func findService(endpoint string, services []ExternalService) (ExternalService, error) {
 	for _, s := range services {
 		if s.Endpoint() == endpoint {
@@ -83,7 +180,6 @@ func (service *Service) draftContentRWCheck() health.Check {
 		PanicGuide:       "https://runbooks.in.ft.com/draft-content-api",
 		Severity:         1,
 		TechnicalSummary: fmt.Sprintf("Draft content RW is not available at %v", service.draftContentRW.Endpoint()),
-		Checker:          externalServiceChecker(service.draftContentRW, "Draft content RW"),
 	}
 }
 
@@ -95,34 +191,119 @@ func (service *Service) contentAPICheck() health.Check {
 		PanicGuide:       "https://runbooks.in.ft.com/draft-content-api",
 		Severity:         1,
 		TechnicalSummary: fmt.Sprintf("Content API is not available at %v", service.uppContentAPI.Endpoint()),
-		Checker:          externalServiceChecker(service.uppContentAPI, "Content API"),
 	}
 }
 
-func externalServiceChecker(s ExternalService, serviceName string) func() (string, error) {
-	return func() (string, error) {
-		if err := s.GTG(); err != nil {
+func externalServiceChecker(s ExternalService, serviceName string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		gtg := s.GTG
+		if cs, ok := s.(contextService); ok {
+			gtg = func() error { return cs.GTGContext(ctx) }
+		}
+		if err := gtg(); err != nil {
 			return fmt.Sprintf("%s is not good-to-go", serviceName), err
 		}
 		return fmt.Sprintf("%s is good-to-go", serviceName), nil
 	}
 }
 
+// GTGChecker fails when a critical check fails. The service remains good-to-go without an optional dependency,
+// as the content types it does not serve are reported by the health check.
 func (service *Service) GTGChecker() gtg.StatusChecker {
 	var fns []gtg.StatusChecker
 
-	for _, c := range service.Checks {
-		fns = append(fns, gtgCheck(c.Checker))
+	for _, c := range service.criticalChecks {
+		fns = append(fns, c.gtgStatus)
 	}
 
 	return gtg.FailFastParallelCheck(fns)
 }
 
-func gtgCheck(handler func() (string, error)) func() gtg.Status {
-	return func() gtg.Status {
-		if _, err := handler(); err != nil {
-			return gtg.Status{GoodToGo: false, Message: err.Error()}
+func (service *Service) capabilitiesCheck() health.Check {
+	return health.Check{
+		ID:               "check-content-type-capabilities",
+		BusinessImpact:   "Drafts of the unavailable content types cannot be read or written",
+		Name:             "Check content type capabilities",
+		PanicGuide:       "https://runbooks.in.ft.com/draft-content-api",
+		Severity:         2,
+		TechnicalSummary: "The validators of the unavailable content types are failing their health checks",
+		Checker:          service.capabilities,
+	}
+}
+
+// capabilities summarises the content types whose drafts can be validated, from the last results of the validator checks.
+func (service *Service) capabilities() (string, error) {
+	var available, unavailable []string
+	for contentType, endpoint := range service.contentTypes {
+		c, found := service.validatorChecks[endpoint]
+		if !found {
+			continue
+		}
+		if result := c.last(); result.err != nil {
+			unavailable = append(unavailable, contentTypeLabel(contentType)+" unavailable")
+		} else {
+			available = append(available, contentTypeLabel(contentType)+" available")
 		}
-		return gtg.Status{GoodToGo: true}
 	}
+	sort.Strings(available)
+	sort.Strings(unavailable)
+
+	if len(unavailable) > 0 {
+		return "", errors.New(strings.Join(append(unavailable, available...), ", "))
+	}
+	if len(available) == 0 {
+		return "No content type validators are checked", nil
+	}
+	return strings.Join(available, ", "), nil
+}
+
+// contentTypeLabel names the content of an UPP content type, e.g. live blog posts for application/vnd.ft-upp-live-blog-post+json.
+func contentTypeLabel(contentType string) string {
+	name, found := strings.CutPrefix(contentType, "application/vnd.ft-upp-")
+	if !found {
+		return contentType
+	}
+	name = strings.TrimSuffix(name, "+json")
+	return strings.ReplaceAll(name, "-", " ") + "s"
+}
+
+func (service *Service) saturationCheck() health.Check {
+	return health.Check{
+		ID:               "check-validator-saturation",
+		BusinessImpact:   "Drafts whose validator is at capacity cannot be read until it catches up",
+		Name:             "Check validator concurrency limits",
+		PanicGuide:       "https://runbooks.in.ft.com/draft-content-api",
+		Severity:         2,
+		TechnicalSummary: "Validators are too slow for the draft reads, the requests exceeding their concurrency limit are rejected with 503",
+		Checker:          service.saturation,
+	}
+}
+
+// saturation reports the concurrent requests to every validator, failing when a validator is at capacity
+// or has rejected requests since the previous check.
+func (service *Service) saturation() (string, error) {
+	service.bulkheadsMutex.Lock()
+	defer service.bulkheadsMutex.Unlock()
+
+	endpoints := make([]string, 0, len(service.bulkheads))
+	for endpoint := range service.bulkheads {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+
+	var usages []string
+	saturated := false
+	for _, endpoint := range endpoints {
+		stats, _ := service.bulkheads[endpoint].BulkheadStats()
+		rejected := stats.Rejected - service.lastRejected[endpoint]
+		service.lastRejected[endpoint] = stats.Rejected
+
+		saturated = saturated || rejected > 0 || stats.InFlight >= stats.Capacity
+		usages = append(usages, fmt.Sprintf("%v %d/%d requests in flight, %d rejected", endpoint, stats.InFlight, stats.Capacity, rejected))
+	}
+
+	if saturated {
+		return "", errors.New(strings.Join(usages, ", "))
+	}
+	return strings.Join(usages, ", "), nil
 }
@@ -1,17 +1,22 @@
 package health
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Financial-Times/draft-content-api/config"
+	"github.com/Financial-Times/draft-content-api/platform"
 	status "github.com/Financial-Times/service-status-go/httphandlers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var mockConfig = config.Config{
@@ -159,6 +164,268 @@ func TestUnhappyGTG(t *testing.T) {
 	cAPI.AssertExpectations(t)
 }
 
+var degradableConfig = config.Config{
+	ContentTypes: map[string]config.ValidatorConfig{
+		"application/vnd.ft-upp-article+json":        {Validator: "spark", Endpoint: "http://article-validator"},
+		"application/vnd.ft-upp-live-blog-post+json": {Validator: "spark", Endpoint: "http://live-blog-post-validator"},
+	},
+	HealthChecks: map[string]config.HealthCheckConfig{
+		"http://article-validator": {
+			ID:          "check-article-validator",
+			CheckerName: "Article validator",
+			Criticality: "critical",
+		},
+		"http://live-blog-post-validator": {
+			ID:          "check-live-blog-post-validator",
+			CheckerName: "Live blog post validator",
+			Criticality: "optional",
+		},
+	},
+}
+
+func mockValidator(endpoint string, gtgErr error) *ExternalServiceMock {
+	srv := new(ExternalServiceMock)
+	srv.On("GTG").Return(gtgErr)
+	srv.On("Endpoint").Return(endpoint)
+
+	return srv
+}
+
+func TestFailingOptionalCheckDoesNotFailGTG(t *testing.T) {
+	articleValidator := mockValidator("http://article-validator", nil)
+	liveBlogPostValidator := mockValidator("http://live-blog-post-validator", errors.New("computer says no"))
+
+	h, err := NewHealthService("", "", "", mockHealthyExternalService(), mockHealthyExternalService(), &degradableConfig,
+		[]ExternalService{articleValidator, liveBlogPostValidator})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	status.NewGoodToGoHandler(h.GTGChecker())(w, httptest.NewRequest("GET", "/__gtg", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestFailingCriticalCheckFailsGTG(t *testing.T) {
+	articleValidator := mockValidator("http://article-validator", errors.New("computer says no"))
+	liveBlogPostValidator := mockValidator("http://live-blog-post-validator", nil)
+
+	h, err := NewHealthService("", "", "", mockHealthyExternalService(), mockHealthyExternalService(), &degradableConfig,
+		[]ExternalService{articleValidator, liveBlogPostValidator})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	status.NewGoodToGoHandler(h.GTGChecker())(w, httptest.NewRequest("GET", "/__gtg", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "computer says no", w.Body.String())
+}
+
+func TestHealthCheckReportsUnavailableContentTypes(t *testing.T) {
+	articleValidator := mockValidator("http://article-validator", nil)
+	liveBlogPostValidator := mockValidator("http://live-blog-post-validator", errors.New("computer says no"))
+
+	h, err := NewHealthService("", "", "", mockHealthyExternalService(), mockHealthyExternalService(), &degradableConfig,
+		[]ExternalService{articleValidator, liveBlogPostValidator})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.HealthCheckHandleFunc()(w, httptest.NewRequest("GET", "/__health", nil))
+
+	hcBody := make(map[string]interface{})
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&hcBody))
+	assert.Len(t, hcBody["checks"], 5)
+
+	var capabilities map[string]interface{}
+	for _, c := range hcBody["checks"].([]interface{}) {
+		if check := c.(map[string]interface{}); check["id"] == "check-content-type-capabilities" {
+			capabilities = check
+		}
+	}
+	require.NotNil(t, capabilities)
+	assert.False(t, capabilities["ok"].(bool))
+	assert.Equal(t, "live blog posts unavailable, articles available", capabilities["checkOutput"])
+}
+
+func TestUnknownCheckCriticality(t *testing.T) {
+	cfg := config.Config{
+		HealthChecks: map[string]config.HealthCheckConfig{
+			"http://cool.api.ft.com/content": {ID: "TestId", Criticality: "sometimes"},
+		},
+	}
+
+	_, err := NewHealthService("", "", "", mockHealthyExternalService(), mockHealthyExternalService(), &cfg,
+		[]ExternalService{mockHealthyExternalService()})
+
+	assert.ErrorContains(t, err, `unknown criticality "sometimes"`)
+}
+
+func TestStartedGTGServesCachedResults(t *testing.T) {
+	draftContentRW := mockHealthyExternalService()
+	cAPI := &countingService{ExternalService: mockHealthyExternalService()}
+	liveBlogPost := mockHealthyExternalService()
+
+	h, err := NewHealthService("", "", "", draftContentRW, cAPI, &mockConfig, []ExternalService{liveBlogPost})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.Start(ctx, time.Hour, time.Minute)
+
+	require.Eventually(t, func() bool {
+		return cAPI.gtgCalls.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		w := httptest.NewRecorder()
+		status.NewGoodToGoHandler(h.GTGChecker())(w, httptest.NewRequest("GET", "/__gtg", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	assert.Equal(t, int32(1), cAPI.gtgCalls.Load())
+}
+
+func TestStartedHealthCheckReportsResultAge(t *testing.T) {
+	draftContentRW := mockHealthyExternalService()
+	cAPI := mockHealthyExternalService()
+	liveBlogPost := mockHealthyExternalService()
+
+	h, err := NewHealthService("", "", "", draftContentRW, cAPI, &mockConfig, []ExternalService{liveBlogPost})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.Start(ctx, time.Hour, time.Minute)
+
+	w := httptest.NewRecorder()
+	h.HealthCheckHandleFunc()(w, httptest.NewRequest("GET", "/__health", nil))
+
+	hcBody := make(map[string]interface{})
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&hcBody))
+	for _, c := range hcBody["checks"].([]interface{}) {
+		check := c.(map[string]interface{})
+		if check["id"] == "check-content-api-health" {
+			assert.Regexp(t, `^Content API is good-to-go \(checked \d+s ago\)$`, check["checkOutput"])
+		}
+	}
+}
+
+func TestStaleCheckResultFailsGTG(t *testing.T) {
+	cached := newCachedCheck(func(context.Context) (string, error) {
+		return "Content API is good-to-go", nil
+	})
+	cached.cache(time.Minute)
+	cached.result = checkResult{output: "Content API is good-to-go", checkedAt: time.Now().Add(-2 * time.Minute)}
+
+	gtgStatus := cached.gtgStatus()
+	assert.False(t, gtgStatus.GoodToGo)
+	assert.Contains(t, gtgStatus.Message, "stale check result")
+
+	_, err := cached.healthCheck()
+	assert.ErrorContains(t, err, "stale check result")
+}
+
+func TestHungCheckIsNotRunAgain(t *testing.T) {
+	var calls atomic.Int32
+	cancelled := make(chan struct{})
+	release := make(chan struct{})
+	cached := newCachedCheck(func(ctx context.Context) (string, error) {
+		if calls.Add(1) > 1 {
+			return "Content API is good-to-go", nil
+		}
+		<-ctx.Done()
+		close(cancelled)
+		<-release
+		return "", ctx.Err()
+	})
+	cached.timeout = 20 * time.Millisecond
+	cached.cache(time.Minute)
+
+	result := cached.refresh()
+	assert.EqualError(t, result.err, "timed out after 20ms")
+	<-cancelled
+
+	assert.EqualError(t, cached.refresh().err, "timed out after 20ms", "the last result is kept while the check is in flight")
+	assert.Equal(t, int32(1), calls.Load(), "the hung check is not run again")
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return cached.refresh().err == nil
+	}, time.Second, 10*time.Millisecond, "the check runs again once the hung one has returned")
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestBackgroundRefreshUpdatesCachedResult(t *testing.T) {
+	draftContentRW := mockHealthyExternalService()
+	liveBlogPost := mockHealthyExternalService()
+
+	cAPIMock := new(ExternalServiceMock)
+	cAPIMock.On("Endpoint").Return("http://cool.api.ft.com/content")
+	cAPIMock.On("GTG").Return(errors.New("computer says no")).Once()
+	cAPIMock.On("GTG").Return(nil)
+	cAPI := &countingService{ExternalService: cAPIMock}
+
+	h, err := NewHealthService("", "", "", draftContentRW, cAPI, &mockConfig, []ExternalService{liveBlogPost})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.Start(ctx, 20*time.Millisecond, time.Minute)
+
+	gtgChecker := h.GTGChecker()
+	require.Eventually(t, func() bool {
+		return cAPI.gtgCalls.Load() > 1 && gtgChecker().GoodToGo
+	}, time.Second, 10*time.Millisecond)
+}
+
+type limitedService struct {
+	*ExternalServiceMock
+	stats platform.BulkheadStats
+}
+
+func (s *limitedService) BulkheadStats() (platform.BulkheadStats, bool) {
+	return s.stats, true
+}
+
+func TestHealthCheckReportsSaturatedValidators(t *testing.T) {
+	articleValidator := &limitedService{
+		ExternalServiceMock: mockValidator("http://article-validator", nil),
+		stats:               platform.BulkheadStats{InFlight: 2, Capacity: 20},
+	}
+	liveBlogPostValidator := &limitedService{
+		ExternalServiceMock: mockValidator("http://live-blog-post-validator", nil),
+		stats:               platform.BulkheadStats{InFlight: 5, Capacity: 5, Rejected: 3},
+	}
+
+	h, err := NewHealthService("", "", "", mockHealthyExternalService(), mockHealthyExternalService(), &degradableConfig,
+		[]ExternalService{articleValidator, liveBlogPostValidator})
+	require.NoError(t, err)
+
+	output, err := h.saturation()
+	assert.Empty(t, output)
+	assert.EqualError(t, err, "http://article-validator 2/20 requests in flight, 0 rejected, "+
+		"http://live-blog-post-validator 5/5 requests in flight, 3 rejected")
+
+	liveBlogPostValidator.stats.InFlight = 1
+	output, err = h.saturation()
+	assert.NoError(t, err, "rejections are counted since the previous check")
+	assert.Equal(t, "http://article-validator 2/20 requests in flight, 0 rejected, "+
+		"http://live-blog-post-validator 1/5 requests in flight, 0 rejected", output)
+
+	w := httptest.NewRecorder()
+	status.NewGoodToGoHandler(h.GTGChecker())(w, httptest.NewRequest("GET", "/__gtg", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "saturation does not fail the GTG")
+}
+
+type countingService struct {
+	ExternalService
+	gtgCalls atomic.Int32
+}
+
+func (s *countingService) GTG() error {
+	s.gtgCalls.Add(1)
+	return s.ExternalService.GTG()
+}
+
 type ExternalServiceMock struct {
 	mock.Mock
 }
@@ -1,17 +1,32 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/Financial-Times/api-endpoint"
+	"github.com/Financial-Times/draft-content-api/audit"
 	"github.com/Financial-Times/draft-content-api/config"
 	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/draft-content-api/events"
 	"github.com/Financial-Times/draft-content-api/health"
+	"github.com/Financial-Times/draft-content-api/monitoring"
+	"github.com/Financial-Times/draft-content-api/platform"
+	"github.com/Financial-Times/draft-content-api/redaction"
+	"github.com/Financial-Times/draft-content-api/secrets"
+	"github.com/Financial-Times/draft-content-api/tracing"
+	"github.com/Financial-Times/draft-content-api/webhooks"
 	"github.com/Financial-Times/go-ft-http/fthttp"
+	"github.com/Financial-Times/go-ft-http/transport"
 	"github.com/Financial-Times/go-logger/v2"
 	"github.com/Financial-Times/http-handlers-go/httphandlers"
 	status "github.com/Financial-Times/service-status-go/httphandlers"
@@ -49,6 +64,13 @@ func main() {
 		EnvVar: "APP_PORT",
 	})
 
+	adminPort := app.String(cli.StringOpt{
+		Name:   "admin-port",
+		Value:  "8081",
+		Desc:   "Port of the support endpoints, such as /__webhooks/deliveries, which is not exposed outside the pod",
+		EnvVar: "ADMIN_PORT",
+	})
+
 	appTimeout := app.String(cli.StringOpt{
 		Name:   "app-timeout",
 		Value:  "8s",
@@ -70,6 +92,41 @@ func main() {
 		EnvVar: "DELIVERY_BASIC_AUTH",
 	})
 
+	deliveryBasicAuthFile := app.String(cli.StringOpt{
+		Name:   "delivery-basic-auth-file",
+		Value:  "",
+		Desc:   "File holding the basic auth (username:password) for access to the delivery UPP clusters, re-read on rotation. Takes precedence over delivery-basic-auth",
+		EnvVar: "DELIVERY_BASIC_AUTH_FILE",
+	})
+
+	deliveryAuthScheme := app.String(cli.StringOpt{
+		Name:   "delivery-auth-scheme",
+		Value:  "basic",
+		Desc:   "Authentication of the requests to the delivery UPP clusters: basic, bearer or api-key",
+		EnvVar: "DELIVERY_AUTH_SCHEME",
+	})
+
+	deliveryTokenFile := app.String(cli.StringOpt{
+		Name:   "delivery-token-file",
+		Value:  "",
+		Desc:   "File holding the bearer token or API key for access to the delivery UPP clusters, re-read on rotation",
+		EnvVar: "DELIVERY_TOKEN_FILE",
+	})
+
+	deliveryAPIKeyHeader := app.String(cli.StringOpt{
+		Name:   "delivery-api-key-header",
+		Value:  "X-Api-Key",
+		Desc:   "Header of the API key for access to the delivery UPP clusters",
+		EnvVar: "DELIVERY_API_KEY_HEADER",
+	})
+
+	secretsRefreshInterval := app.String(cli.StringOpt{
+		Name:   "secrets-refresh-interval",
+		Value:  "30s",
+		Desc:   "Interval between the reads of the secret files, to pick up rotated secrets. 0 reads them only at startup",
+		EnvVar: "SECRETS_REFRESH_INTERVAL",
+	})
+
 	contentEndpoint := app.String(cli.StringOpt{
 		Name:   "content-endpoint",
 		Value:  "http://localhost:8081/content",
@@ -77,6 +134,20 @@ func main() {
 		EnvVar: "CONTENT_ENDPOINT",
 	})
 
+	contentProvider := app.String(cli.StringOpt{
+		Name:   "content-provider",
+		Value:  "upp",
+		Desc:   "Where published content is read from when there is no draft: upp for the Content API, fixtures for JSON files on disk, or disabled",
+		EnvVar: "CONTENT_PROVIDER",
+	})
+
+	contentFixturesDir := app.String(cli.StringOpt{
+		Name:   "content-fixtures-dir",
+		Value:  "./fixtures",
+		Desc:   "Directory of <uuid>.json published content fixtures, used when content-provider is fixtures",
+		EnvVar: "CONTENT_FIXTURES_DIR",
+	})
+
 	xPolicies := app.Strings(cli.StringsOpt{
 		Name:   "x-policies",
 		Desc:   "The x-policies to apply with a request to the UPP Delivery cluster",
@@ -104,6 +175,305 @@ func main() {
 		EnvVar: "VALIDATOR_YML",
 	})
 
+	draftStore := app.String(cli.StringOpt{
+		Name:   "draft-store",
+		Value:  "generic-rw",
+		Desc:   "Where drafts are stored: generic-rw for the content RW, local for an embedded store on disk",
+		EnvVar: "DRAFT_STORE",
+	})
+
+	draftStorePath := app.String(cli.StringOpt{
+		Name:   "draft-store-path",
+		Value:  "./drafts.db",
+		Desc:   "Location of the embedded draft store file, used when draft-store is local",
+		EnvVar: "DRAFT_STORE_PATH",
+	})
+
+	draftHistoryVersions := app.Int(cli.IntOpt{
+		Name:   "draft-history-versions",
+		Value:  10,
+		Desc:   "Number of previous versions kept for each draft by the draft store, 0 disables draft history",
+		EnvVar: "DRAFT_HISTORY_VERSIONS",
+	})
+
+	draftEventsPublisher := app.String(cli.StringOpt{
+		Name:   "draft-events-publisher",
+		Value:  "none",
+		Desc:   "Where draft changed events are published: kafka, log or none",
+		EnvVar: "DRAFT_EVENTS_PUBLISHER",
+	})
+
+	kafkaAddresses := app.Strings(cli.StringsOpt{
+		Name:   "kafka-addresses",
+		Desc:   "Kafka brokers draft changed events are published to",
+		EnvVar: "KAFKA_ADDRESSES",
+	})
+
+	kafkaDraftEventsTopic := app.String(cli.StringOpt{
+		Name:   "kafka-draft-events-topic",
+		Value:  "DraftContentChangedEvents",
+		Desc:   "Kafka topic draft changed events are published to",
+		EnvVar: "KAFKA_DRAFT_EVENTS_TOPIC",
+	})
+
+	draftEventsQueueSize := app.Int(cli.IntOpt{
+		Name:   "draft-events-queue-size",
+		Value:  1000,
+		Desc:   "Number of draft changed events queued for publishing",
+		EnvVar: "DRAFT_EVENTS_QUEUE_SIZE",
+	})
+
+	draftEventsMaxRetries := app.Int(cli.IntOpt{
+		Name:   "draft-events-max-retries",
+		Value:  5,
+		Desc:   "Number of times publishing a draft changed event is retried with an increasing backoff, before it is retried at the longest backoff",
+		EnvVar: "DRAFT_EVENTS_MAX_RETRIES",
+	})
+
+	draftEventsOutboxPath := app.String(cli.StringOpt{
+		Name:   "draft-events-outbox-path",
+		Value:  "",
+		Desc:   "Embedded database file keeping the unpublished draft changed events across restarts, they are only kept in memory when empty",
+		EnvVar: "DRAFT_EVENTS_OUTBOX_PATH",
+	})
+
+	draftEventsFeed := app.String(cli.StringOpt{
+		Name:   "draft-events-feed",
+		Value:  "local",
+		Desc:   "Where the draft events streamed on /drafts/events come from: kafka (the writes of every instance) or local (the writes of this instance)",
+		EnvVar: "DRAFT_EVENTS_FEED",
+	})
+
+	draftEventsStreamMaxConnections := app.Int(cli.IntOpt{
+		Name:   "draft-events-stream-max-connections",
+		Value:  100,
+		Desc:   "Number of concurrent draft events streams",
+		EnvVar: "DRAFT_EVENTS_STREAM_MAX_CONNECTIONS",
+	})
+
+	draftEventsStreamMaxUUIDs := app.Int(cli.IntOpt{
+		Name:   "draft-events-stream-max-uuids",
+		Value:  50,
+		Desc:   "Number of drafts a single draft events stream can follow",
+		EnvVar: "DRAFT_EVENTS_STREAM_MAX_UUIDS",
+	})
+
+	draftEventsStreamHeartbeat := app.String(cli.StringOpt{
+		Name:   "draft-events-stream-heartbeat",
+		Value:  "15s",
+		Desc:   "Interval of the heartbeats sent on idle draft events streams",
+		EnvVar: "DRAFT_EVENTS_STREAM_HEARTBEAT",
+	})
+
+	draftEventsStreamMaxAge := app.String(cli.StringOpt{
+		Name:   "draft-events-stream-max-age",
+		Value:  "10m",
+		Desc:   "Time after which a draft events stream is closed and the client has to reconnect",
+		EnvVar: "DRAFT_EVENTS_STREAM_MAX_AGE",
+	})
+
+	webhooksQueueSize := app.Int(cli.IntOpt{
+		Name:   "webhooks-queue-size",
+		Value:  100,
+		Desc:   "Number of payloads scheduled per webhook and event type, the others wait in the outbox",
+		EnvVar: "WEBHOOKS_QUEUE_SIZE",
+	})
+
+	webhooksMaxRetries := app.Int(cli.IntOpt{
+		Name:   "webhooks-max-retries",
+		Value:  5,
+		Desc:   "Number of times delivering a webhook payload is retried",
+		EnvVar: "WEBHOOKS_MAX_RETRIES",
+	})
+
+	webhooksOutboxDir := app.String(cli.StringOpt{
+		Name:   "webhooks-outbox-dir",
+		Value:  "",
+		Desc:   "Directory of the database files keeping webhook payloads until they are delivered, across restarts. Payloads are kept in memory if it is empty",
+		EnvVar: "WEBHOOKS_OUTBOX_DIR",
+	})
+
+	webhooksDeliveryLogSize := app.Int(cli.IntOpt{
+		Name:   "webhooks-delivery-log-size",
+		Value:  200,
+		Desc:   "Number of recent webhook deliveries listed on /__webhooks/deliveries of the admin port",
+		EnvVar: "WEBHOOKS_DELIVERY_LOG_SIZE",
+	})
+
+	shutdownGracePeriod := app.String(cli.StringOpt{
+		Name:   "shutdown-grace-period",
+		Value:  "20s",
+		Desc:   "Time given to in-flight requests to complete on shutdown",
+		EnvVar: "SHUTDOWN_GRACE_PERIOD",
+	})
+
+	shutdownDrainDelay := app.String(cli.StringOpt{
+		Name:   "shutdown-drain-delay",
+		Value:  "5s",
+		Desc:   "Time between /__ready failing and the server no longer accepting requests on shutdown",
+		EnvVar: "SHUTDOWN_DRAIN_DELAY",
+	})
+
+	shutdownCloseTimeout := app.String(cli.StringOpt{
+		Name:   "shutdown-close-timeout",
+		Value:  "4s",
+		Desc:   "Time given to the background queues to flush once requests are drained on shutdown. With the drain delay and the grace period, it must fit in the termination grace period of the pod",
+		EnvVar: "SHUTDOWN_CLOSE_TIMEOUT",
+	})
+
+	httpReadTimeout := app.String(cli.StringOpt{
+		Name:   "http-read-timeout",
+		Value:  "10s",
+		Desc:   "Maximum duration for reading a whole request, including its body",
+		EnvVar: "HTTP_READ_TIMEOUT",
+	})
+
+	httpWriteTimeout := app.String(cli.StringOpt{
+		Name:   "http-write-timeout",
+		Value:  "15s",
+		Desc:   "Maximum duration before timing out writes of a response, draft events streams excepted",
+		EnvVar: "HTTP_WRITE_TIMEOUT",
+	})
+
+	httpIdleTimeout := app.String(cli.StringOpt{
+		Name:   "http-idle-timeout",
+		Value:  "120s",
+		Desc:   "Maximum time to wait for the next request on a keep-alive connection",
+		EnvVar: "HTTP_IDLE_TIMEOUT",
+	})
+
+	validatorMaxConcurrentRequests := app.Int(cli.IntOpt{
+		Name:   "validator-max-concurrent-requests",
+		Value:  20,
+		Desc:   "Concurrent requests to a validator, unless set in the validator YML file. Excess draft reads get a 503",
+		EnvVar: "VALIDATOR_MAX_CONCURRENT_REQUESTS",
+	})
+
+	maxDraftBodySize := app.Int(cli.IntOpt{
+		Name:   "max-draft-body-size",
+		Value:  5 << 20,
+		Desc:   "Maximum size in bytes of a draft or merge patch, unless set for the content type in the validator YML file. Larger writes get a 413, 0 disables the limit",
+		EnvVar: "MAX_DRAFT_BODY_SIZE",
+	})
+
+	idempotencyWindow := app.String(cli.StringOpt{
+		Name:   "idempotency-window",
+		Value:  "10m",
+		Desc:   "Time during which a draft write retried with the same Idempotency-Key returns the original outcome, 0 disables Idempotency-Key support",
+		EnvVar: "IDEMPOTENCY_WINDOW",
+	})
+
+	idempotencyMaxKeys := app.Int(cli.IntOpt{
+		Name:   "idempotency-max-keys",
+		Value:  10000,
+		Desc:   "Number of Idempotency-Key outcomes kept in memory, the oldest are forgotten first",
+		EnvVar: "IDEMPOTENCY_MAX_KEYS",
+	})
+
+	auditSink := app.String(cli.StringOpt{
+		Name:   "audit-sink",
+		Value:  "none",
+		Desc:   "Where draft audit events are recorded, stdout, file, http or none to only keep the recent ones in memory",
+		EnvVar: "AUDIT_SINK",
+	})
+
+	auditFile := app.String(cli.StringOpt{
+		Name:   "audit-file",
+		Value:  "draft-audit.jsonl",
+		Desc:   "JSON lines file the draft audit events are appended to by the file audit sink",
+		EnvVar: "AUDIT_FILE",
+	})
+
+	auditURL := app.String(cli.StringOpt{
+		Name:   "audit-url",
+		Value:  "",
+		Desc:   "URL the draft audit events are posted to by the http audit sink",
+		EnvVar: "AUDIT_URL",
+	})
+
+	auditReads := app.Bool(cli.BoolOpt{
+		Name:   "audit-reads",
+		Value:  false,
+		Desc:   "Whether draft reads are audited, as well as writes",
+		EnvVar: "AUDIT_READS",
+	})
+
+	auditQueueSize := app.Int(cli.IntOpt{
+		Name:   "audit-queue-size",
+		Value:  1000,
+		Desc:   "Number of audit events queued for the audit sink",
+		EnvVar: "AUDIT_QUEUE_SIZE",
+	})
+
+	auditStoreEvents := app.Int(cli.IntOpt{
+		Name:   "audit-store-events",
+		Value:  50,
+		Desc:   "Recent audit events kept in memory per draft, for /drafts/audit/{uuid}",
+		EnvVar: "AUDIT_STORE_EVENTS",
+	})
+
+	auditStoreDrafts := app.Int(cli.IntOpt{
+		Name:   "audit-store-drafts",
+		Value:  1000,
+		Desc:   "Most recently audited drafts whose events are kept in memory",
+		EnvVar: "AUDIT_STORE_DRAFTS",
+	})
+
+	loadSheddingLatencyTarget := app.String(cli.StringOpt{
+		Name:   "load-shedding-latency-target",
+		Value:  "2s",
+		Desc:   "Latency above which the concurrency limit of draft reads and writes decreases, 0 disables load shedding",
+		EnvVar: "LOAD_SHEDDING_LATENCY_TARGET",
+	})
+
+	loadSheddingMinLimit := app.Int(cli.IntOpt{
+		Name:   "load-shedding-min-limit",
+		Value:  5,
+		Desc:   "Lowest concurrency limit of draft reads and writes",
+		EnvVar: "LOAD_SHEDDING_MIN_LIMIT",
+	})
+
+	loadSheddingMaxLimit := app.Int(cli.IntOpt{
+		Name:   "load-shedding-max-limit",
+		Value:  100,
+		Desc:   "Highest, and initial, concurrency limit of draft reads and writes",
+		EnvVar: "LOAD_SHEDDING_MAX_LIMIT",
+	})
+
+	healthCheckInterval := app.String(cli.StringOpt{
+		Name:   "health-check-interval",
+		Value:  "10s",
+		Desc:   "How often the dependencies are checked in the background for /__health and /__gtg",
+		EnvVar: "HEALTH_CHECK_INTERVAL",
+	})
+
+	healthCheckStaleAfter := app.String(cli.StringOpt{
+		Name:   "health-check-stale-after",
+		Value:  "60s",
+		Desc:   "Age after which a check result is no longer trusted and the check fails",
+		EnvVar: "HEALTH_CHECK_STALE_AFTER",
+	})
+
+	tracingExporter := app.String(cli.StringOpt{
+		Name:   "tracing-exporter",
+		Value:  "none",
+		Desc:   "Where OpenTelemetry spans are exported: otlp, stdout or none",
+		EnvVar: "TRACING_EXPORTER",
+	})
+
+	tracingOTLPEndpoint := app.String(cli.StringOpt{
+		Name:   "tracing-otlp-endpoint",
+		Desc:   "OTLP/HTTP traces endpoint, defaults to the OTEL_EXPORTER_OTLP_* variables",
+		EnvVar: "TRACING_OTLP_ENDPOINT",
+	})
+
+	tracingSampleRatio := app.Float64(cli.Float64Opt{
+		Name:   "tracing-sample-ratio",
+		Value:  1,
+		Desc:   "Ratio of the traces started by the service that are sampled",
+		EnvVar: "TRACING_SAMPLE_RATIO",
+	})
+
 	logLevel := app.String(cli.StringOpt{
 		Name:   "logLevel",
 		Value:  "INFO",
@@ -112,6 +482,9 @@ func main() {
 	})
 
 	log := logger.NewUPPLogger(*appName, *logLevel)
+	// the redaction configuration is read later on, the credentials are redacted from the start
+	redactor, _ := redaction.NewRedactor(nil, nil)
+	redaction.Install(log, redactor)
 	log.Infof("[Startup] %s is starting", *appName)
 
 	app.Action = func() {
@@ -122,16 +495,31 @@ func main() {
 			log.WithError(err).Fatal("invalid content policy")
 		}
 
+		shutdownTracing, err := tracing.Setup(context.Background(), *tracingExporter, *tracingOTLPEndpoint, *appSystemCode, *tracingSampleRatio)
+		if err != nil {
+			log.WithError(err).Fatal("unable to set up tracing")
+		}
+		defer shutdownTracing(context.Background())
+
 		timeout, err := time.ParseDuration(*appTimeout)
 		if err != nil {
 			log.Fatalf("App could not start, error=[%s]\n", err)
 		}
 
+		serverConfig, err := parseServerConfig(*port, *adminPort, *httpReadTimeout, *httpWriteTimeout, *httpIdleTimeout, *shutdownGracePeriod, *shutdownDrainDelay, *shutdownCloseTimeout)
+		if err != nil {
+			log.WithError(err).Fatal("invalid server configuration")
+		}
+
 		validatorConfig, err := config.ReadConfig(*validatorYml)
 		if err != nil {
 			log.WithError(err).Fatal("unable to read r/w YAML configuration")
 		}
 
+		if err = redactor.Configure(validatorConfig.Redaction.Fields, validatorConfig.Redaction.DraftPaths); err != nil {
+			log.WithError(err).Fatal("invalid redaction configuration")
+		}
+
 		httpClient, err := fthttp.NewClient(
 			fthttp.WithTimeout(timeout),
 			fthttp.WithUserAgent("PAC"),
@@ -144,27 +532,209 @@ func main() {
 
 		content.AllowedOriginSystemIDValues = getOriginID(*originIDs)
 
-		contentTypeMapping := buildContentTypeMapping(validatorConfig, httpClient, log)
+		contentTypeMapping := buildContentTypeMapping(validatorConfig, timeout, *validatorMaxConcurrentRequests, *appSystemCode, log)
 
-		resolver := content.NewDraftContentValidatorResolver(contentTypeMapping)
-		draftContentRWService := content.NewDraftContentRWService(*contentRWEndpoint, resolver, httpClient)
+		promMetrics := monitoring.NewMetrics()
+		resolver := monitoring.InstrumentValidatorResolver(content.NewDraftContentValidatorResolver(contentTypeMapping), promMetrics)
+		var draftContentRWService content.DraftContentRW
+		switch *draftStore {
+		case "generic-rw":
+			draftContentRWService = content.NewDraftContentHistoryRWService(*contentRWEndpoint, resolver, httpClient, *draftHistoryVersions)
+		case "local":
+			draftContentRWService, err = content.NewLocalDraftContentRWService(*draftStorePath, resolver, *draftHistoryVersions)
+			if err != nil {
+				log.WithError(err).WithField("path", *draftStorePath).Fatal("unable to open local draft store")
+			}
+			log.WithField("path", *draftStorePath).Info("using local draft store")
+		default:
+			log.WithField("DraftStore", *draftStore).Fatal("Unknown draft store")
+		}
+
+		draftContentRWService = monitoring.InstrumentDraftContentRW(draftContentRWService, promMetrics)
 
 		content.AllowedContentTypes = getAllowedContentType(validatorConfig)
-		basicAuthCredentials := strings.Split(*deliveryBasicAuth, ":")
-		if len(basicAuthCredentials) != 2 {
-			log.Fatal("error while resolving basic auth")
+
+		// closers release the resources of the service once it has stopped serving requests, in reverse order,
+		// flushing their queues until the close timeout
+		var closers []func(ctx context.Context)
+
+		var cAPI content.ContentProviderAPI
+		switch *contentProvider {
+		case "upp":
+			refresh, err := time.ParseDuration(*secretsRefreshInterval)
+			if err != nil {
+				log.WithError(err).Fatal("invalid secrets refresh interval")
+			}
+			auth, closeAuth, err := deliveryAuthenticator(*deliveryAuthScheme, *deliveryBasicAuth, *deliveryBasicAuthFile, *deliveryTokenFile, *deliveryAPIKeyHeader, refresh, redactor, log)
+			if err != nil {
+				log.WithError(err).Fatal("error while resolving the delivery credentials")
+			}
+			closers = append(closers, func(context.Context) { closeAuth() })
+			cAPI = content.NewAuthenticatedContentAPI(*contentEndpoint, auth, *xPolicies, httpClient)
+		case "fixtures":
+			cAPI = content.NewFixtureContentProvider(*contentFixturesDir)
+			log.WithField("dir", *contentFixturesDir).Info("using published content fixtures")
+		case "disabled":
+			cAPI = content.NewDisabledContentProvider()
+			log.Info("published content fallback is disabled")
+		default:
+			log.WithField("ContentProvider", *contentProvider).Fatal("Unknown content provider")
+		}
+		cAPI = monitoring.InstrumentContentProvider(cAPI, promMetrics)
+
+		if closer, ok := draftContentRWService.(io.Closer); ok {
+			closers = append(closers, func(context.Context) { closer.Close() })
+		}
+
+		handlerOptions := []content.HandlerOption{
+			content.WithWriteLimiter(content.NewWriteLimiter(validatorConfig.WriteLimits, promMetrics)),
+			content.WithMaxBodySizes(int64(*maxDraftBodySize), maxBodySizes(validatorConfig)),
+			content.WithUUIDPointers(uuidPointers(validatorConfig)),
 		}
 
-		cAPI := content.NewContentAPI(*contentEndpoint, basicAuthCredentials[0], basicAuthCredentials[1], *xPolicies, httpClient)
+		window, err := time.ParseDuration(*idempotencyWindow)
+		if err != nil {
+			log.WithError(err).Fatal("invalid idempotency window")
+		}
+		if window > 0 {
+			handlerOptions = append(handlerOptions, content.WithIdempotencyStore(content.NewMemoryIdempotencyStore(window, *idempotencyMaxKeys)))
+		}
 
-		contentHandler := content.NewHandler(cAPI, draftContentRWService, timeout, log)
+		var sink audit.Sink
+		switch *auditSink {
+		case "none":
+		case "stdout":
+			sink = audit.NewWriterSink(os.Stdout)
+		case "file":
+			sink, err = audit.NewFileSink(*auditFile)
+			if err != nil {
+				log.WithError(err).WithField("AuditFile", *auditFile).Fatal("Unable to open the audit file")
+			}
+		case "http":
+			if *auditURL == "" {
+				log.Fatal("the http audit sink needs an audit URL")
+			}
+			sink = audit.NewHTTPSink(*auditURL, httpClient)
+		default:
+			log.WithField("AuditSink", *auditSink).Fatal("Unknown audit sink")
+		}
+		auditor := audit.NewAuditor(sink, audit.NewStore(*auditStoreEvents, *auditStoreDrafts), *auditReads, *auditQueueSize, promMetrics, log)
+		handlerOptions = append(handlerOptions, content.WithDraftAuditor(auditor))
+		closers = append(closers, auditor.Close)
+
+		latencyTarget, err := time.ParseDuration(*loadSheddingLatencyTarget)
+		if err != nil {
+			log.WithError(err).Fatal("invalid load shedding latency target")
+		}
+		if latencyTarget > 0 {
+			limiter := content.NewAdaptiveLimiter(latencyTarget, *loadSheddingMinLimit, *loadSheddingMaxLimit, promMetrics)
+			handlerOptions = append(handlerOptions, content.WithAdaptiveLimiter(limiter))
+		}
+		var publisher events.Publisher
+		switch *draftEventsPublisher {
+		case "none":
+		case "log":
+			publisher = events.NewLogPublisher(log)
+		case "kafka":
+			publisher = events.NewKafkaPublisher(*kafkaAddresses, *kafkaDraftEventsTopic)
+		default:
+			log.WithField("DraftEventsPublisher", *draftEventsPublisher).Fatal("Unknown draft events publisher")
+		}
+		if publisher != nil {
+			outboxStore := events.NewMemoryOutboxStore()
+			if *draftEventsOutboxPath != "" {
+				outboxStore, err = events.NewBoltOutboxStore(*draftEventsOutboxPath)
+				if err != nil {
+					log.WithError(err).WithField("path", *draftEventsOutboxPath).Fatal("unable to open draft events outbox")
+				}
+			} else {
+				log.Warn("unpublished draft changed events are only kept in memory, and are lost on restart")
+			}
+			outbox := events.NewOutbox(publisher, outboxStore, *draftEventsQueueSize, *draftEventsMaxRetries, time.Second, log)
+			handlerOptions = append(handlerOptions, content.WithDraftObserver(outbox))
+			closer, _ := publisher.(io.Closer)
+			closers = append(closers, func(ctx context.Context) {
+				outbox.Close(ctx)
+				if closer != nil {
+					closer.Close()
+				}
+			})
+		}
+
+		webhookDeliveries := webhooks.NewDeliveryLog(*webhooksDeliveryLogSize)
+		if len(validatorConfig.Webhooks) > 0 {
+			if *webhooksOutboxDir == "" {
+				log.Warn("webhook payloads are kept in memory, and lost on restart")
+			}
+			dispatcher, err := webhooks.NewDispatcher(validatorConfig.Webhooks, httpClient, *webhooksQueueSize, *webhooksMaxRetries, time.Second, *webhooksOutboxDir, webhookDeliveries, log)
+			if err != nil {
+				log.WithError(err).Fatal("invalid webhooks configuration")
+			}
+			handlerOptions = append(handlerOptions, content.WithDraftObserver(dispatcher), content.WithDraftValidationObserver(dispatcher))
+			closers = append(closers, dispatcher.Close)
+			log.WithField("webhooks", len(validatorConfig.Webhooks)).Info("draft events are posted to webhooks")
+		}
+
+		heartbeat, err := time.ParseDuration(*draftEventsStreamHeartbeat)
+		if err != nil {
+			log.WithError(err).Fatal("invalid draft events stream heartbeat")
+		}
+		maxStreamAge, err := time.ParseDuration(*draftEventsStreamMaxAge)
+		if err != nil {
+			log.WithError(err).Fatal("invalid draft events stream max age")
+		}
+		broker := events.NewBroker(events.BrokerConfig{
+			HistorySize:           100,
+			MaxConnections:        *draftEventsStreamMaxConnections,
+			MaxUUIDsPerConnection: *draftEventsStreamMaxUUIDs,
+			BufferSize:            20,
+			Heartbeat:             heartbeat,
+			MaxConnectionAge:      maxStreamAge,
+		}, log)
+		switch *draftEventsFeed {
+		case "local":
+			handlerOptions = append(handlerOptions, content.WithDraftObserver(broker))
+		case "kafka":
+			if *draftEventsPublisher != "kafka" {
+				log.Fatal("the kafka draft events feed needs the kafka draft events publisher")
+			}
+			feed := events.NewKafkaChangeFeed(*kafkaAddresses, *kafkaDraftEventsTopic, broker, log)
+			feedCtx, stopFeed := context.WithCancel(context.Background())
+			go feed.Run(feedCtx)
+			closers = append(closers, func(context.Context) {
+				stopFeed()
+				feed.Close()
+			})
+		default:
+			log.WithField("DraftEventsFeed", *draftEventsFeed).Fatal("Unknown draft events feed")
+		}
+
+		contentHandler := content.NewHandler(cAPI, draftContentRWService, timeout, log, handlerOptions...)
 		healthService, err := health.NewHealthService(*appSystemCode, *appName, defaultAppDescription, draftContentRWService, cAPI,
 			validatorConfig, extractServices(contentTypeMapping))
 		if err != nil {
 			log.WithError(err).Fatal("Unable to create health service")
 		}
+		checkInterval, err := time.ParseDuration(*healthCheckInterval)
+		if err != nil {
+			log.WithError(err).Fatal("invalid health check interval")
+		}
+		checkStaleAfter, err := time.ParseDuration(*healthCheckStaleAfter)
+		if err != nil {
+			log.WithError(err).Fatal("invalid health check staleness threshold")
+		}
+		checksCtx, stopChecks := context.WithCancel(context.Background())
+		healthService.Start(checksCtx, checkInterval, checkStaleAfter)
+		closers = append(closers, func(context.Context) { stopChecks() })
+
+		serveEndpoints(serverConfig, apiYml, contentHandler, broker, auditor, webhookDeliveries, promMetrics, healthService, log)
 
-		serveEndpoints(*port, apiYml, contentHandler, healthService, log)
+		closeCtx, cancelClose := context.WithTimeout(context.Background(), serverConfig.closeTimeout)
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i](closeCtx)
+		}
+		cancelClose()
+		log.Info("[Shutdown] stopped")
 	}
 	err := app.Run(os.Args)
 	if err != nil {
@@ -192,12 +762,21 @@ func extractServices(dcm map[string]content.DraftContentValidator) []health.Exte
 	return result
 }
 
-func buildContentTypeMapping(validatorConfig *config.Config, httpClient *http.Client, log *logger.UPPLogger) map[string]content.DraftContentValidator {
+// buildContentTypeMapping isolates the validators from each other and from the other services:
+// every validator client has its own connection pool, sized for its concurrency limit, and rejects the requests exceeding it.
+func buildContentTypeMapping(validatorConfig *config.Config, timeout time.Duration, defaultMaxConcurrentRequests int, systemCode string, log *logger.UPPLogger) map[string]content.DraftContentValidator {
 	contentTypeMapping := map[string]content.DraftContentValidator{}
 
 	for contentType, cfg := range validatorConfig.ContentTypes {
 		var service content.DraftContentValidator
 
+		maxConcurrentRequests := cfg.MaxConcurrentRequests
+		if maxConcurrentRequests <= 0 {
+			maxConcurrentRequests = defaultMaxConcurrentRequests
+		}
+		httpClient := platform.NewIsolatedClient(timeout, maxConcurrentRequests, log,
+			&transport.TIDFromContextExtension{}, transport.NewUserAgentExtension(platform.StandardUserAgent("PAC", systemCode)))
+
 		switch cfg.Validator {
 		case "spark":
 			service = content.NewSparkDraftContentValidatorService(cfg.Endpoint, httpClient)
@@ -210,16 +789,123 @@ func buildContentTypeMapping(validatorConfig *config.Config, httpClient *http.Cl
 			WithField("Content-Type", contentType).
 			WithField("Endpoint", cfg.Endpoint).
 			WithField("Validator", cfg.Validator).
+			WithField("MaxConcurrentRequests", maxConcurrentRequests).
 			Info("added validator service")
 	}
 
 	return contentTypeMapping
 }
 
-func serveEndpoints(port string, apiYml *string, contentHandler *content.Handler, healthService *health.Service, log *logger.UPPLogger) {
+func maxBodySizes(validatorConfig *config.Config) map[string]int64 {
+	sizes := map[string]int64{}
+	for contentType, cfg := range validatorConfig.ContentTypes {
+		if cfg.MaxBodySize > 0 {
+			sizes[contentType] = cfg.MaxBodySize
+		}
+	}
+	return sizes
+}
+
+func uuidPointers(validatorConfig *config.Config) map[string]string {
+	pointers := map[string]string{}
+	for contentType, cfg := range validatorConfig.ContentTypes {
+		if cfg.UUIDPointer != "" {
+			pointers[contentType] = cfg.UUIDPointer
+		}
+	}
+	return pointers
+}
+
+// deliveryAuthenticator authenticates the requests to the delivery clusters with the credentials of the scheme,
+// read from their secret file when there is one and kept registered with the redactor as they rotate.
+// The returned func stops watching the secret file.
+func deliveryAuthenticator(scheme, basicAuth, basicAuthFile, tokenFile, apiKeyHeader string, refresh time.Duration, redactor *redaction.Redactor, log *logger.UPPLogger) (content.RequestAuthenticator, func(), error) {
+	switch scheme {
+	case "basic":
+		if basicAuthFile == "" {
+			if err := secrets.ValidateBasicAuth(basicAuth); err != nil {
+				return nil, nil, fmt.Errorf("delivery %w", err)
+			}
+			username, password, _ := strings.Cut(basicAuth, ":")
+			redactor.SetBasicAuth("delivery-basic-auth", username, password)
+			return content.NewBasicAuth(content.StaticCredential(basicAuth)), func() {}, nil
+		}
+
+		secret, err := secrets.NewFile(basicAuthFile, refresh, secrets.ValidateBasicAuth, log)
+		if err != nil {
+			return nil, nil, err
+		}
+		secret.OnChange(func(credentials string) {
+			username, password, _ := strings.Cut(credentials, ":")
+			redactor.SetBasicAuth("delivery-basic-auth", username, password)
+		})
+		return content.NewBasicAuth(secret.Value), secret.Close, nil
+	case "bearer", "api-key":
+		if tokenFile == "" {
+			return nil, nil, fmt.Errorf("the %s delivery auth scheme needs a token file", scheme)
+		}
+
+		secret, err := secrets.NewFile(tokenFile, refresh, nil, log)
+		if err != nil {
+			return nil, nil, err
+		}
+		secret.OnChange(func(token string) {
+			redactor.SetSecrets("delivery-token", token)
+		})
+		if scheme == "bearer" {
+			return content.NewBearerAuth(secret.Value), secret.Close, nil
+		}
+		return content.NewAPIKeyAuth(apiKeyHeader, secret.Value), secret.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown delivery auth scheme %q", scheme)
+	}
+}
+
+type serverConfig struct {
+	port         string
+	adminPort    string
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	idleTimeout  time.Duration
+	gracePeriod  time.Duration
+	drainDelay   time.Duration
+	closeTimeout time.Duration
+}
+
+func parseServerConfig(port, adminPort, readTimeout, writeTimeout, idleTimeout, gracePeriod, drainDelay, closeTimeout string) (serverConfig, error) {
+	cfg := serverConfig{port: port, adminPort: adminPort}
+	durations := []struct {
+		value string
+		into  *time.Duration
+	}{
+		{readTimeout, &cfg.readTimeout},
+		{writeTimeout, &cfg.writeTimeout},
+		{idleTimeout, &cfg.idleTimeout},
+		{gracePeriod, &cfg.gracePeriod},
+		{drainDelay, &cfg.drainDelay},
+		{closeTimeout, &cfg.closeTimeout},
+	}
+	for _, d := range durations {
+		var err error
+		if *d.into, err = time.ParseDuration(d.value); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// serveEndpoints serves requests until the process is asked to terminate, then drains them:
+// /__ready fails first so that no new traffic is routed to the pod, and in-flight requests are given the grace period to complete.
+func serveEndpoints(cfg serverConfig, apiYml *string, contentHandler *content.Handler, broker *events.Broker, auditor *audit.Auditor, webhookDeliveries *webhooks.DeliveryLog, promMetrics *monitoring.Metrics, healthService *health.Service, log *logger.UPPLogger) {
 	r := vestigo.NewRouter()
-	r.Get("/drafts/content/:uuid", contentHandler.ReadContent)
+	r.Get("/drafts/content/:uuid", promMetrics.ObserveContentReads(http.HandlerFunc(contentHandler.ReadContent)).ServeHTTP)
+	r.Get("/drafts/content/:uuid/diff", contentHandler.DiffContent)
+	r.Get("/drafts/content/:uuid/versions", contentHandler.ListDraftVersions)
+	r.Get("/drafts/content/:uuid/versions/:writeRequestId", contentHandler.ReadDraftVersion)
+	r.Get("/drafts/nativecontent/:uuid", contentHandler.ReadNativeContent)
 	r.Put("/drafts/nativecontent/:uuid", contentHandler.WriteNativeContent)
+	r.Patch("/drafts/nativecontent/:uuid", contentHandler.PatchNativeContent)
+	r.Get("/drafts/audit/:uuid", auditor.ServeAudit)
 
 	if apiYml != nil {
 		apiEndpoint, err := api.NewAPIEndpointForFile(*apiYml)
@@ -237,15 +923,83 @@ func serveEndpoints(port string, apiYml *string, contentHandler *content.Handler
 	monitoringRouter = httphandlers.TransactionAwareRequestLoggingHandler(log, monitoringRouter)
 	monitoringRouter = httphandlers.HTTPMetricsHandler(metrics.DefaultRegistry, monitoringRouter)
 
+	// draft events streams outlive the server write timeout, which they lift on their unwrapped response writer
+	eventsRouter := vestigo.NewRouter()
+	eventsRouter.Get("/drafts/events", broker.ServeEvents)
+
+	var draining atomic.Bool
+
 	http.HandleFunc("/__health", healthService.HealthCheckHandleFunc())
 	http.HandleFunc(status.GTGPath, status.NewGoodToGoHandler(healthService.GTGChecker()))
 	http.HandleFunc(status.BuildInfoPath, status.BuildInfoHandler)
+	http.HandleFunc("/__ready", readinessHandler(&draining))
+	http.HandleFunc("/__live", livenessHandler)
+	http.Handle("/metrics", promMetrics.Handler())
 
+	http.Handle("/drafts/events", eventsRouter)
 	http.Handle("/", monitoringRouter)
 
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
-		log.Fatalf("Unable to start: %v", err)
+	server := &http.Server{
+		Addr:         ":" + cfg.port,
+		ReadTimeout:  cfg.readTimeout,
+		WriteTimeout: cfg.writeTimeout,
+		IdleTimeout:  cfg.idleTimeout,
+	}
+	server.RegisterOnShutdown(broker.Close)
+
+	// the delivery log lists the webhooks and their errors, it is only served inside the pod
+	adminRouter := http.NewServeMux()
+	adminRouter.Handle("/__webhooks/deliveries", webhookDeliveries)
+	adminServer := &http.Server{
+		Addr:         ":" + cfg.adminPort,
+		Handler:      adminRouter,
+		ReadTimeout:  cfg.readTimeout,
+		WriteTimeout: cfg.writeTimeout,
+		IdleTimeout:  cfg.idleTimeout,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Unable to start: %v", err)
+		}
+	}()
+	go func() {
+		if err := adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Unable to start the admin server: %v", err)
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-signals
+
+	log.WithField("signal", sig.String()).Infof("[Shutdown] draining requests for %v", cfg.drainDelay)
+	draining.Store(true)
+	time.Sleep(cfg.drainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.gracePeriod)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.WithError(err).Warn("[Shutdown] requests were still in flight at the end of the grace period")
 	}
+	adminServer.Shutdown(ctx)
+}
+
+// readinessHandler fails once the service starts draining, so that it stops receiving traffic before shutting down.
+func readinessHandler(draining *atomic.Bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if draining.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("draining"))
+			return
+		}
+		w.Write([]byte("OK"))
+	}
+}
+
+// livenessHandler succeeds as long as the service can handle requests, dependencies are checked by /__gtg.
+func livenessHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Write([]byte("OK"))
 }
 
 func getOriginID(s string) map[string]struct{} {
@@ -0,0 +1,139 @@
+package monitoring
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/go-logger/v2"
+)
+
+type instrumentedDraftContentRW struct {
+	content.DraftContentRW
+	metrics *Metrics
+}
+
+type instrumentedDraftContentHistoryRW struct {
+	*instrumentedDraftContentRW
+	content.DraftContentHistory
+}
+
+// InstrumentDraftContentRW records the duration and outcome of the draft store reads and writes.
+// The draft history of the store, if any, is still available to the Handler.
+func InstrumentDraftContentRW(rw content.DraftContentRW, metrics *Metrics) content.DraftContentRW {
+	instrumented := &instrumentedDraftContentRW{rw, metrics}
+	if history, ok := rw.(content.DraftContentHistory); ok {
+		return &instrumentedDraftContentHistoryRW{instrumented, history}
+	}
+	return instrumented
+}
+
+func (rw *instrumentedDraftContentRW) Read(ctx context.Context, contentUUID string, log *logger.UPPLogger) (io.ReadCloser, content.DraftMetadata, error) {
+	start := time.Now()
+	body, metadata, err := rw.DraftContentRW.Read(ctx, contentUUID, log)
+	rw.metrics.rwDuration.WithLabelValues("read", outcome(err)).Observe(time.Since(start).Seconds())
+	return body, metadata, err
+}
+
+func (rw *instrumentedDraftContentRW) ReadNative(ctx context.Context, contentUUID string, log *logger.UPPLogger) (io.ReadCloser, content.DraftMetadata, error) {
+	start := time.Now()
+	body, metadata, err := rw.DraftContentRW.ReadNative(ctx, contentUUID, log)
+	rw.metrics.rwDuration.WithLabelValues("read_native", outcome(err)).Observe(time.Since(start).Seconds())
+	return body, metadata, err
+}
+
+func (rw *instrumentedDraftContentRW) Write(ctx context.Context, contentUUID string, draft io.Reader, headers map[string]string, log *logger.UPPLogger) error {
+	start := time.Now()
+	err := rw.DraftContentRW.Write(ctx, contentUUID, draft, headers, log)
+	rw.metrics.rwDuration.WithLabelValues("write", outcome(err)).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// Close closes the draft store if it needs closing.
+func (rw *instrumentedDraftContentRW) Close() error {
+	if closer, ok := rw.DraftContentRW.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// GTGContext checks the draft store until ctx is done, if it supports it.
+func (rw *instrumentedDraftContentRW) GTGContext(ctx context.Context) error {
+	return gtgContext(ctx, rw.DraftContentRW)
+}
+
+type instrumentedResolver struct {
+	content.DraftContentValidatorResolver
+	metrics *Metrics
+}
+
+// InstrumentValidatorResolver records the duration and outcome of the validator requests,
+// and counts the drafts failing validation by reason.
+func InstrumentValidatorResolver(resolver content.DraftContentValidatorResolver, metrics *Metrics) content.DraftContentValidatorResolver {
+	return &instrumentedResolver{resolver, metrics}
+}
+
+func (r *instrumentedResolver) ValidatorForContentType(contentType string) (content.DraftContentValidator, error) {
+	validator, err := r.DraftContentValidatorResolver.ValidatorForContentType(contentType)
+	if err != nil {
+		r.metrics.validationFailures.WithLabelValues(mediaType(contentType), outcomeUnsupportedContentType).Inc()
+		return nil, err
+	}
+	return &instrumentedValidator{validator, r.metrics}, nil
+}
+
+type instrumentedValidator struct {
+	content.DraftContentValidator
+	metrics *Metrics
+}
+
+func (v *instrumentedValidator) Validate(ctx context.Context, contentUUID string, nativeBody io.Reader, contentType string, log *logger.UPPLogger) (io.ReadCloser, error) {
+	start := time.Now()
+	body, err := v.DraftContentValidator.Validate(ctx, contentUUID, nativeBody, contentType, log)
+	result := validatorOutcome(err)
+	v.metrics.validatorDuration.WithLabelValues(mediaType(contentType), v.Endpoint(), result).Observe(time.Since(start).Seconds())
+	if err != nil {
+		v.metrics.validationFailures.WithLabelValues(mediaType(contentType), result).Inc()
+	}
+	return body, err
+}
+
+// mediaType drops the parameters of a content type, so that they do not multiply the metric labels.
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+	return contentType
+}
+
+type instrumentedContentProvider struct {
+	content.ContentProviderAPI
+	metrics *Metrics
+}
+
+// InstrumentContentProvider records the duration and outcome of the published content requests.
+func InstrumentContentProvider(provider content.ContentProviderAPI, metrics *Metrics) content.ContentProviderAPI {
+	return &instrumentedContentProvider{provider, metrics}
+}
+
+func (p *instrumentedContentProvider) Get(ctx context.Context, contentUUID string, log *logger.UPPLogger) (*http.Response, error) {
+	start := time.Now()
+	resp, err := p.ContentProviderAPI.Get(ctx, contentUUID, log)
+	p.metrics.contentAPIDuration.WithLabelValues(statusOutcome(resp, err)).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+// GTGContext checks the content provider until ctx is done, if it supports it.
+func (p *instrumentedContentProvider) GTGContext(ctx context.Context) error {
+	return gtgContext(ctx, p.ContentProviderAPI)
+}
+
+func gtgContext(ctx context.Context, service interface{ GTG() error }) error {
+	if cs, ok := service.(interface{ GTGContext(context.Context) error }); ok {
+		return cs.GTGContext(ctx)
+	}
+	return service.GTG()
+}
@@ -0,0 +1,174 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Financial-Times/draft-content-api/audit"
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/go-logger/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUUID        = "83a201c6-60cd-11e7-91a7-502f7ee26895"
+	testContentType = "application/vnd.ft-upp-article+json"
+)
+
+type stubDraftContentRW struct {
+	content.DraftContentRW
+	err error
+}
+
+func (rw *stubDraftContentRW) Read(context.Context, string, *logger.UPPLogger) (io.ReadCloser, content.DraftMetadata, error) {
+	return nil, content.DraftMetadata{}, rw.err
+}
+
+func (rw *stubDraftContentRW) Write(context.Context, string, io.Reader, map[string]string, *logger.UPPLogger) error {
+	return rw.err
+}
+
+type stubDraftContentHistoryRW struct {
+	stubDraftContentRW
+	content.DraftContentHistory
+}
+
+func TestInstrumentDraftContentRW(t *testing.T) {
+	m := NewMetrics()
+	rw := InstrumentDraftContentRW(&stubDraftContentRW{err: content.ErrDraftNotFound}, m)
+
+	_, _, err := rw.Read(context.TODO(), testUUID, logger.NewUPPLogger("test logger", "debug"))
+
+	assert.Equal(t, content.ErrDraftNotFound, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(m.rwDuration))
+	assert.Equal(t, uint64(1), histogramCount(t, m, "read", outcomeNotFound))
+}
+
+func TestInstrumentDraftContentRWKeepsHistory(t *testing.T) {
+	m := NewMetrics()
+	rw := InstrumentDraftContentRW(&stubDraftContentHistoryRW{}, m)
+
+	_, ok := rw.(content.DraftContentHistory)
+	assert.True(t, ok)
+
+	_, ok = InstrumentDraftContentRW(&stubDraftContentRW{}, m).(content.DraftContentHistory)
+	assert.False(t, ok)
+}
+
+func TestInstrumentValidatorResolver(t *testing.T) {
+	validatorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message": "invalid draft"}`))
+	}))
+	defer validatorServer.Close()
+
+	m := NewMetrics()
+	validator := content.NewSparkDraftContentValidatorService(validatorServer.URL, http.DefaultClient)
+	resolver := InstrumentValidatorResolver(content.NewDraftContentValidatorResolver(map[string]content.DraftContentValidator{
+		testContentType: validator,
+	}), m)
+
+	_, err := resolver.ValidatorForContentType("application/vnd.ft-upp-unknown+json")
+	assert.Error(t, err)
+
+	v, err := resolver.ValidatorForContentType(testContentType)
+	require.NoError(t, err)
+	_, err = v.Validate(context.TODO(), testUUID, strings.NewReader("{}"), testContentType+"; charset=utf-8", logger.NewUPPLogger("test logger", "debug"))
+	assert.Error(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.validationFailures.WithLabelValues("application/vnd.ft-upp-unknown+json", outcomeUnsupportedContentType)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.validationFailures.WithLabelValues(testContentType, outcomeInvalid)))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.validatorDuration))
+}
+
+func TestInstrumentContentProvider(t *testing.T) {
+	m := NewMetrics()
+	provider := InstrumentContentProvider(content.NewDisabledContentProvider(), m)
+
+	resp, err := provider.Get(context.TODO(), testUUID, logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 1, testutil.CollectAndCount(m.contentAPIDuration))
+}
+
+func TestObserveContentReads(t *testing.T) {
+	m := NewMetrics()
+	handler := m.ObserveContentReads(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Source", "published")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/drafts/content/"+testUUID, nil))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.contentReads.WithLabelValues("published")))
+}
+
+func TestMetricsHandler(t *testing.T) {
+	m := NewMetrics()
+	InstrumentDraftContentRW(&stubDraftContentRW{err: errors.New("test error")}, m).
+		Write(context.TODO(), testUUID, strings.NewReader(""), map[string]string{}, logger.NewUPPLogger("test logger", "debug"))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `draft_content_api_rw_request_duration_seconds_count{operation="write",outcome="error"} 1`)
+}
+
+func histogramCount(t *testing.T, m *Metrics, labels ...string) uint64 {
+	families, err := m.registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "draft_content_api_rw_request_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matches := true
+			for i, label := range metric.GetLabel() {
+				matches = matches && label.GetValue() == labels[i]
+			}
+			if matches {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+func TestDraftWriteLimited(t *testing.T) {
+	m := NewMetrics()
+	var observer content.WriteLimitObserver = m
+
+	observer.DraftWriteLimited("cct", content.WriteLimitInFlight)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.writesLimited.WithLabelValues("cct", content.WriteLimitInFlight)))
+}
+
+func TestLoadShedding(t *testing.T) {
+	m := NewMetrics()
+	var observer content.LoadSheddingObserver = m
+
+	observer.RequestShed(content.PriorityRead)
+	observer.ConcurrencyLimitChanged(42)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requestsShed.WithLabelValues(content.PriorityRead)))
+	assert.Equal(t, 42.0, testutil.ToFloat64(m.concurrencyLimit))
+}
+
+func TestAuditEventDropped(t *testing.T) {
+	m := NewMetrics()
+	var observer audit.DropObserver = m
+
+	observer.AuditEventDropped(audit.DropReasonQueueFull)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.auditEventsDropped.WithLabelValues(audit.DropReasonQueueFull)))
+}
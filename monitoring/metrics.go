@@ -0,0 +1,189 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/draft-content-api/platform"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "draft_content_api"
+
+	outcomeSuccess                = "success"
+	outcomeNotFound               = "not_found"
+	outcomeInvalid                = "invalid"
+	outcomeUnsupportedContentType = "unsupported_content_type"
+	outcomeTimeout                = "timeout"
+	outcomeRejected               = "rejected"
+	outcomeConflict               = "conflict"
+	outcomeError                  = "error"
+)
+
+// Metrics holds the Prometheus collectors of the service.
+type Metrics struct {
+	registry           *prometheus.Registry
+	rwDuration         *prometheus.HistogramVec
+	validatorDuration  *prometheus.HistogramVec
+	validationFailures *prometheus.CounterVec
+	contentAPIDuration *prometheus.HistogramVec
+	contentReads       *prometheus.CounterVec
+	writesLimited      *prometheus.CounterVec
+	requestsShed       *prometheus.CounterVec
+	concurrencyLimit   prometheus.Gauge
+	auditEventsDropped *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		rwDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rw_request_duration_seconds",
+			Help:      "Duration of the draft store requests, by operation and outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "outcome"}),
+		validatorDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "validator_request_duration_seconds",
+			Help:      "Duration of the draft validator requests, by content type, validator endpoint and outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"content_type", "endpoint", "outcome"}),
+		validationFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "validation_failures_total",
+			Help:      "Drafts that could not be validated, by content type and reason.",
+		}, []string{"content_type", "reason"}),
+		contentAPIDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "content_api_request_duration_seconds",
+			Help:      "Duration of the published content requests, by HTTP status or error.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"outcome"}),
+		contentReads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "content_reads_total",
+			Help:      "Content reads, by whether the draft or the published content fallback was returned.",
+		}, []string{"source"}),
+		writesLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "draft_writes_limited_total",
+			Help:      "Draft writes rejected by the write limits, by origin system and exceeded limit.",
+		}, []string{"origin", "limit"}),
+		requestsShed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_shed_total",
+			Help:      "Draft reads and writes shed by the adaptive concurrency limit, by priority.",
+		}, []string{"priority"}),
+		concurrencyLimit: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "adaptive_concurrency_limit",
+			Help:      "Current adaptive limit of the concurrent draft reads and writes.",
+		}),
+		auditEventsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "audit_events_dropped_total",
+			Help:      "Audit events that could not be recorded to the audit sink, by reason.",
+		}, []string{"reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.rwDuration,
+		m.validatorDuration,
+		m.validationFailures,
+		m.contentAPIDuration,
+		m.contentReads,
+		m.writesLimited,
+		m.requestsShed,
+		m.concurrencyLimit,
+		m.auditEventsDropped,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveContentReads counts the content reads answered by next by their X-Content-Source header,
+// giving the rate of reads falling back to published content.
+func (m *Metrics) ObserveContentReads(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		if source := w.Header().Get("X-Content-Source"); source != "" {
+			m.contentReads.WithLabelValues(source).Inc()
+		}
+	})
+}
+
+// DraftWriteLimited counts the draft writes rejected by the content.WriteLimiter.
+func (m *Metrics) DraftWriteLimited(originSystemID string, limit string) {
+	m.writesLimited.WithLabelValues(originSystemID, limit).Inc()
+}
+
+// RequestShed counts the requests shed by the content.AdaptiveLimiter.
+func (m *Metrics) RequestShed(priority string) {
+	m.requestsShed.WithLabelValues(priority).Inc()
+}
+
+// ConcurrencyLimitChanged records the limit of the content.AdaptiveLimiter.
+func (m *Metrics) ConcurrencyLimitChanged(limit int) {
+	m.concurrencyLimit.Set(float64(limit))
+}
+
+// AuditEventDropped counts the audit events the audit.Auditor could not record.
+func (m *Metrics) AuditEventDropped(reason string) {
+	m.auditEventsDropped.WithLabelValues(reason).Inc()
+}
+
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return outcomeSuccess
+	case errors.Is(err, content.ErrDraftNotFound):
+		return outcomeNotFound
+	case errors.Is(err, content.ErrDraftNotValid):
+		return outcomeInvalid
+	case errors.Is(err, content.ErrDraftContentTypeNotSupported):
+		return outcomeUnsupportedContentType
+	case errors.Is(err, context.DeadlineExceeded):
+		return outcomeTimeout
+	case errors.Is(err, platform.ErrBulkheadFull):
+		return outcomeRejected
+	case errors.Is(err, content.ErrDraftModified):
+		return outcomeConflict
+	default:
+		return outcomeError
+	}
+}
+
+// validatorOutcome maps validator errors the way the draft store does when reading drafts.
+func validatorOutcome(err error) string {
+	var validatorError content.ValidatorError
+	if errors.As(err, &validatorError) {
+		switch validatorError.StatusCode() {
+		case http.StatusNotFound, http.StatusUnsupportedMediaType:
+			return outcomeUnsupportedContentType
+		case http.StatusUnprocessableEntity:
+			return outcomeInvalid
+		}
+	}
+	return outcome(err)
+}
+
+func statusOutcome(resp *http.Response, err error) string {
+	if err != nil {
+		return outcome(err)
+	}
+	return strconv.Itoa(resp.StatusCode)
+}
@@ -0,0 +1,163 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Financial-Times/go-ft-http/transport"
+	"github.com/Financial-Times/go-logger/v2"
+	"github.com/Financial-Times/service-status-go/buildinfo"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+)
+
+// ErrBulkheadFull is returned for requests exceeding the concurrency of a Bulkhead.
+var ErrBulkheadFull = errors.New("too many concurrent requests to the service")
+
+type bulkheadExemptKey struct{}
+
+// BulkheadStats describes the usage of a Bulkhead.
+type BulkheadStats struct {
+	InFlight int
+	Capacity int
+	Rejected uint64
+}
+
+// Bulkhead bounds the concurrent requests to a service, failing the excess ones immediately,
+// so that a slow service cannot hold the goroutines and connections needed to serve the others.
+// A request holds its slot until its response body is closed.
+type Bulkhead struct {
+	next     http.RoundTripper
+	slots    chan struct{}
+	rejected atomic.Uint64
+}
+
+func NewBulkhead(next http.RoundTripper, maxConcurrent int) *Bulkhead {
+	return &Bulkhead{
+		next:  next,
+		slots: make(chan struct{}, maxConcurrent),
+	}
+}
+
+func (b *Bulkhead) RoundTrip(req *http.Request) (*http.Response, error) {
+	if exempt, _ := req.Context().Value(bulkheadExemptKey{}).(bool); exempt {
+		return b.next.RoundTrip(req)
+	}
+
+	select {
+	case b.slots <- struct{}{}:
+	default:
+		b.rejected.Add(1)
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, ErrBulkheadFull
+	}
+
+	resp, err := b.next.RoundTrip(req)
+	if err != nil {
+		<-b.slots
+		return nil, err
+	}
+	resp.Body = &slotReleasingBody{ReadCloser: resp.Body, release: func() { <-b.slots }}
+	return resp, nil
+}
+
+func (b *Bulkhead) Stats() BulkheadStats {
+	return BulkheadStats{
+		InFlight: len(b.slots),
+		Capacity: cap(b.slots),
+		Rejected: b.rejected.Load(),
+	}
+}
+
+// BulkheadStats reports the usage of the Bulkhead of the service client, if it has one.
+func (svc *Service) BulkheadStats() (BulkheadStats, bool) {
+	if b, ok := svc.httpClient.Transport.(*Bulkhead); ok {
+		return b.Stats(), true
+	}
+	return BulkheadStats{}, false
+}
+
+type slotReleasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *slotReleasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// withoutBulkhead lets health checks reach a saturated service.
+func withoutBulkhead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bulkheadExemptKey{}, true)
+}
+
+// NewIsolatedClient returns a client with its own connection pool behind a Bulkhead of maxConcurrent requests,
+// instead of the http.DefaultTransport shared by the other clients. The pool keeps a connection for the health checks.
+// The extensions, such as the transaction ID and the standard User-Agent, are applied to every request,
+// and the requests are logged like those of transport.WithLogger when log is not nil.
+// transport.NewTransport is not used as it always sends the requests through http.DefaultTransport.
+func NewIsolatedClient(timeout time.Duration, maxConcurrent int, log *logger.UPPLogger, extensions ...transport.HTTPRequestExtension) *http.Client {
+	pool := http.DefaultTransport.(*http.Transport).Clone()
+	pool.MaxConnsPerHost = maxConcurrent + 1
+	pool.MaxIdleConnsPerHost = maxConcurrent + 1
+
+	var next http.RoundTripper = pool
+	if log != nil {
+		next = &loggingTransport{next: next, log: log}
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: NewBulkhead(&extendingTransport{next: next, extensions: extensions}, maxConcurrent),
+	}
+}
+
+// StandardUserAgent is the User-Agent of transport.WithStandardUserAgent, e.g. PAC-draft-content-api/<version>.
+func StandardUserAgent(platform string, systemCode string) string {
+	return strings.ReplaceAll(strings.ToUpper(platform)+"-"+strings.ToLower(systemCode)+"/"+buildinfo.GetBuildInfo().Version, " ", "-")
+}
+
+type extendingTransport struct {
+	next       http.RoundTripper
+	extensions []transport.HTTPRequestExtension
+}
+
+func (t *extendingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, e := range t.extensions {
+		e.ExtendRequest(req)
+	}
+	return t.next.RoundTrip(req)
+}
+
+type loggingTransport struct {
+	next http.RoundTripper
+	log  *logger.UPPLogger
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	requestLog := t.log.WithFields(map[string]interface{}{
+		"responsetime":   time.Since(start).Milliseconds(),
+		"method":         req.Method,
+		"transaction_id": tidutils.GetTransactionIDFromRequest(req),
+		"requestURL":     req.URL.String(),
+		"userAgent":      req.UserAgent(),
+	})
+	if err == nil {
+		requestLog = requestLog.WithField("status", resp.Status)
+	}
+	requestLog.Info()
+	return resp, err
+}
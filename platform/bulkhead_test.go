@@ -0,0 +1,98 @@
+package platform
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-ft-http/transport"
+	"github.com/Financial-Times/go-logger/v2"
+	status "github.com/Financial-Times/service-status-go/httphandlers"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkheadRejectsExcessRequests(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewIsolatedClient(5*time.Second, 1, nil)
+	svc := NewService(server.URL, client)
+
+	done := make(chan error)
+	go func() {
+		resp, err := client.Get(server.URL + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+	require.Eventually(t, func() bool {
+		stats, _ := svc.BulkheadStats()
+		return stats.InFlight == 1
+	}, time.Second, 10*time.Millisecond)
+
+	_, err := client.Get(server.URL + "/fast")
+	assert.ErrorIs(t, err, ErrBulkheadFull)
+	assert.NoError(t, svc.GTG(), "health checks are not limited")
+
+	stats, limited := svc.BulkheadStats()
+	assert.True(t, limited)
+	assert.Equal(t, BulkheadStats{InFlight: 1, Capacity: 1, Rejected: 1}, stats)
+
+	release <- struct{}{}
+	require.NoError(t, <-done)
+
+	resp, err := client.Get(server.URL + "/fast")
+	require.NoError(t, err, "closing the response body frees its slot")
+	resp.Body.Close()
+}
+
+func TestIsolatedClientExtendsRequests(t *testing.T) {
+	var userAgent, tid string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		tid = r.Header.Get(tidutils.TransactionIDHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewIsolatedClient(5*time.Second, 1, logger.NewUPPLogger("test logger", "debug"),
+		&transport.TIDFromContextExtension{}, transport.NewUserAgentExtension(StandardUserAgent("PAC", "draft-content-api")))
+	req, err := http.NewRequestWithContext(tidutils.TransactionAwareContext(context.Background(), "tid_test"), http.MethodGet, server.URL+status.GTGPath, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.True(t, strings.HasPrefix(userAgent, "PAC-draft-content-api/"), userAgent)
+	assert.Equal(t, "tid_test", tid)
+}
+
+func TestServiceWithoutBulkhead(t *testing.T) {
+	_, limited := NewService("http://localhost", http.DefaultClient).BulkheadStats()
+	assert.False(t, limited)
+}
+
+func TestIsolatedClientsHaveTheirOwnPool(t *testing.T) {
+	first := NewIsolatedClient(5*time.Second, 3, nil)
+	second := NewIsolatedClient(5*time.Second, 3, nil)
+
+	firstPool := first.Transport.(*Bulkhead).next.(*extendingTransport).next.(*http.Transport)
+	secondPool := second.Transport.(*Bulkhead).next.(*extendingTransport).next.(*http.Transport)
+	assert.NotSame(t, firstPool, secondPool)
+	assert.NotSame(t, http.DefaultTransport, firstPool)
+	assert.Equal(t, 4, firstPool.MaxConnsPerHost, "a connection is kept for the health checks")
+	assert.Equal(t, 4, firstPool.MaxIdleConnsPerHost)
+}
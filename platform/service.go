@@ -1,6 +1,7 @@
 package platform
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -19,8 +20,13 @@ func NewService(endpoint string, httpClient *http.Client) *Service {
 }
 
 func (svc *Service) GTG() error {
+	return svc.GTGContext(context.Background())
+}
+
+// GTGContext calls the good-to-go endpoint of the service until ctx is done.
+func (svc *Service) GTGContext(ctx context.Context) error {
 	reqURI := svc.endpoint + status.GTGPath
-	req, err := http.NewRequest("GET", reqURI, nil)
+	req, err := http.NewRequestWithContext(withoutBulkhead(ctx), "GET", reqURI, nil)
 	if err != nil {
 		return fmt.Errorf("gtg request error: %v", err.Error())
 	}
@@ -32,11 +38,12 @@ func (svc *Service) GTG() error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		errMsgBody, err := io.ReadAll(resp.Body)
+		// the body is not quoted, upstream error pages may echo credentials or request content
+		size, err := io.Copy(io.Discard, resp.Body)
 		if err != nil {
 			return errors.New("gtg returned a non-200 HTTP status")
 		}
-		return fmt.Errorf("gtg returned a non-200 HTTP status: %v - %v", resp.StatusCode, string(errMsgBody))
+		return fmt.Errorf("gtg returned a non-200 HTTP status: %v (%d bytes)", resp.StatusCode, size)
 	}
 	return nil
 }
@@ -25,7 +25,7 @@ func TestUnhappyGTG(t *testing.T) {
 	testClient, err := fthttp.NewClient(fthttp.WithSysInfo("PAC", "awesome-service"))
 	assert.NoError(t, err)
 	svc := NewService(server.URL, testClient)
-	assert.EqualError(t, svc.GTG(), "gtg returned a non-200 HTTP status: 503 - I not am happy!")
+	assert.EqualError(t, svc.GTG(), "gtg returned a non-200 HTTP status: 503 (15 bytes)")
 }
 
 func TestGTGInvalidURL(t *testing.T) {
@@ -0,0 +1,35 @@
+package redaction
+
+import (
+	stdlog "log"
+
+	"github.com/Financial-Times/go-logger/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type formatter struct {
+	next     logrus.Formatter
+	redactor *Redactor
+}
+
+// Install redacts every entry of the logger, and of the standard logrus logger, before it is formatted.
+// The standard library logger, used by net/http among others, is written through the logger.
+// Libraries writing to stdout or stderr by other means are not covered.
+func Install(log *logger.UPPLogger, redactor *Redactor) {
+	log.Formatter = &formatter{next: log.Formatter, redactor: redactor}
+	std := logrus.StandardLogger()
+	std.Formatter = &formatter{next: std.Formatter, redactor: redactor}
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(log.WriterLevel(logrus.ErrorLevel))
+}
+
+// Format formats a redacted copy of the entry, as the fields of an entry are shared with the entries derived from it.
+func (f *formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	redacted := *entry
+	redacted.Message = f.redactor.String(entry.Message)
+	redacted.Data = make(logrus.Fields, len(entry.Data))
+	for name, value := range entry.Data {
+		redacted.Data[name] = f.redactor.Field(name, value)
+	}
+	return f.next.Format(&redacted)
+}
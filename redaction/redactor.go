@@ -0,0 +1,348 @@
+package redaction
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces the redacted values.
+const Redacted = "[REDACTED]"
+
+// minSecretLength avoids redacting every occurrence of a trivial value, such as an empty or placeholder password.
+const minSecretLength = 4
+
+// defaultSensitiveFields are always redacted, whatever the configuration.
+var defaultSensitiveFields = []string{"password", "secret", "authorization", "token", "apikey", "credentials"}
+
+var (
+	urlPasswordPattern   = regexp.MustCompile(`(://[^:/@\s]+:)[^@\s/]+@`)
+	authorizationPattern = regexp.MustCompile(`(?i)\b(basic|bearer)\s+[A-Za-z0-9+/=._~-]{8,}`)
+)
+
+// Redactor removes credentials, and the sensitive parts of drafts, from the values written to the logs:
+//   - fields whose names contain a sensitive word, such as password, are redacted whole
+//   - registered secrets, passwords of URLs and Authorization header values are redacted wherever they appear
+//   - the values at the draft paths, JSON pointers such as /bodyXML, are redacted from any JSON object quoted in a value,
+//     such as the payload of an upstream error, so that embargoed stories never reach the logs
+type Redactor struct {
+	mu              sync.RWMutex
+	sensitiveFields []string
+	draftPaths      [][]string
+	secrets         map[string][]string
+	// longestFirst holds every secret, longest first so that a secret containing another one is redacted whole
+	longestFirst []string
+}
+
+// NewRedactor returns a Redactor of the default sensitive fields and of the given ones.
+func NewRedactor(sensitiveFields []string, draftPaths []string) (*Redactor, error) {
+	r := &Redactor{secrets: map[string][]string{}}
+	if err := r.Configure(sensitiveFields, draftPaths); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Configure replaces the configured sensitive fields and draft paths, so that a Redactor installed
+// before the configuration is read covers the startup logs.
+func (r *Redactor) Configure(sensitiveFields []string, draftPaths []string) error {
+	var fields []string
+	for _, field := range append(defaultSensitiveFields, sensitiveFields...) {
+		fields = append(fields, normaliseFieldName(field))
+	}
+	var paths [][]string
+	for _, path := range draftPaths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("draft path %q is not a JSON pointer", path)
+		}
+		tokens := strings.Split(path[1:], "/")
+		for i, token := range tokens {
+			tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		}
+		paths = append(paths, tokens)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sensitiveFields = fields
+	r.draftPaths = paths
+	return nil
+}
+
+// SetSecrets registers the current values of a secret, replacing its previous ones, e.g. on rotation.
+func (r *Redactor) SetSecrets(name string, values ...string) {
+	var secrets []string
+	for _, value := range values {
+		if len(value) >= minSecretLength {
+			secrets = append(secrets, value)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.secrets[name] = secrets
+	r.longestFirst = r.longestFirst[:0]
+	for _, values := range r.secrets {
+		r.longestFirst = append(r.longestFirst, values...)
+	}
+	sort.Slice(r.longestFirst, func(i, j int) bool { return len(r.longestFirst[i]) > len(r.longestFirst[j]) })
+}
+
+// SetBasicAuth registers basic auth credentials as a secret, both as they are and encoded in an Authorization header.
+func (r *Redactor) SetBasicAuth(name string, username string, password string) {
+	credentials := username + ":" + password
+	r.SetSecrets(name, password, credentials, base64.StdEncoding.EncodeToString([]byte(credentials)))
+}
+
+// Field returns the value to log for the field.
+func (r *Redactor) Field(name string, value interface{}) interface{} {
+	if r.isSensitiveField(name) {
+		return Redacted
+	}
+
+	switch v := value.(type) {
+	case string:
+		return r.String(v)
+	case error:
+		return r.String(v.Error())
+	default:
+		return value
+	}
+}
+
+// String redacts the secrets and the sensitive parts of drafts from s.
+func (r *Redactor) String(s string) string {
+	r.mu.RLock()
+	for _, secret := range r.longestFirst {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	r.mu.RUnlock()
+
+	s = urlPasswordPattern.ReplaceAllString(s, "${1}"+Redacted+"@")
+	s = authorizationPattern.ReplaceAllString(s, "${1} "+Redacted)
+	return r.redactQuotedDrafts(s)
+}
+
+func (r *Redactor) isSensitiveField(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name = normaliseFieldName(name)
+	for _, field := range r.sensitiveFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactQuotedDrafts redacts the draft paths of the JSON objects found in s, leaving the rest of s untouched.
+// Objects quoted as escaped strings, such as drafts formatted with %q, are unquoted to be redacted and quoted again.
+func (r *Redactor) redactQuotedDrafts(s string) string {
+	r.mu.RLock()
+	draftPaths := r.draftPaths
+	r.mu.RUnlock()
+
+	if len(draftPaths) == 0 || !strings.Contains(s, "{") {
+		return s
+	}
+	return redactObjects(s, draftPaths)
+}
+
+func redactObjects(s string, draftPaths [][]string) string {
+	var redacted strings.Builder
+	written := 0
+	for _, span := range quotedObjects(s) {
+		var replacement string
+		var ok bool
+		if span.escaped {
+			replacement, ok = redactEscapedObject(s[span.start:span.end], draftPaths)
+		} else {
+			replacement, ok = redactObject(s[span.start:span.end], draftPaths)
+		}
+		if !ok {
+			continue
+		}
+		redacted.WriteString(s[written:span.start])
+		redacted.WriteString(replacement)
+		written = span.end
+	}
+
+	if written == 0 {
+		return s
+	}
+	redacted.WriteString(s[written:])
+	return redacted.String()
+}
+
+// objectSpan is the position of an object in a string, escaped if it is quoted as a string itself.
+type objectSpan struct {
+	start   int
+	end     int
+	escaped bool
+}
+
+// quotedObjects returns the outermost balanced braces of s, and the strings of s holding an object, in a single pass,
+// so that each of them is decoded once: an object nested in braces which are not JSON is not redacted.
+func quotedObjects(s string) []objectSpan {
+	var spans []objectSpan
+	var open []int
+	inString, escaped, unterminated := false, false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			if len(open) > 0 {
+				inString = true
+				continue
+			}
+			if unterminated || i+1 == len(s) || s[i+1] != '{' {
+				continue
+			}
+			end := stringEnd(s, i)
+			if end < 0 {
+				unterminated = true
+				continue
+			}
+			if end-i >= 4 && s[end-2] == '}' {
+				spans = append(spans, objectSpan{start: i, end: end, escaped: true})
+				i = end - 1
+			}
+		case '{':
+			open = append(open, i)
+		case '}':
+			if len(open) == 0 {
+				continue
+			}
+			start := open[len(open)-1]
+			open = open[:len(open)-1]
+			// the objects nested in this one are decoded with it
+			for len(spans) > 0 && spans[len(spans)-1].start > start {
+				spans = spans[:len(spans)-1]
+			}
+			spans = append(spans, objectSpan{start: start, end: i + 1})
+		}
+	}
+	return spans
+}
+
+// stringEnd returns the position following the closing quote of the string opened at start, or -1 if it is not closed.
+func stringEnd(s string, start int) int {
+	escaped := false
+	for i := start + 1; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func redactObject(quoted string, draftPaths [][]string) (string, bool) {
+	dec := json.NewDecoder(strings.NewReader(quoted))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil || !redactDraftPaths(doc, draftPaths) {
+		return "", false
+	}
+
+	var redacted bytes.Buffer
+	enc := json.NewEncoder(&redacted)
+	enc.SetEscapeHTML(false)
+	enc.Encode(doc)
+	return string(bytes.TrimSuffix(redacted.Bytes(), []byte("\n"))), true
+}
+
+// redactEscapedObject redacts the objects of a string quoted by Go or as JSON, and quotes it again the same way.
+func redactEscapedObject(literal string, draftPaths [][]string) (string, bool) {
+	quote := strconv.Quote
+	unquoted, err := strconv.Unquote(literal)
+	if err != nil {
+		if json.Unmarshal([]byte(literal), &unquoted) != nil {
+			return "", false
+		}
+		quote = func(s string) string {
+			var quoted bytes.Buffer
+			enc := json.NewEncoder(&quoted)
+			enc.SetEscapeHTML(false)
+			enc.Encode(s)
+			return strings.TrimSuffix(quoted.String(), "\n")
+		}
+	}
+
+	redacted := redactObjects(unquoted, draftPaths)
+	if redacted == unquoted {
+		return "", false
+	}
+	return quote(redacted), true
+}
+
+func redactDraftPaths(doc map[string]interface{}, draftPaths [][]string) bool {
+	redacted := false
+	for _, path := range draftPaths {
+		if redactPath(doc, path) {
+			redacted = true
+		}
+	}
+	return redacted
+}
+
+func redactPath(node interface{}, path []string) bool {
+	for _, token := range path[:len(path)-1] {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			node = n[token]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return false
+			}
+			node = n[i]
+		default:
+			return false
+		}
+	}
+
+	last := path[len(path)-1]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if _, found := n[last]; found {
+			n[last] = Redacted
+			return true
+		}
+	case []interface{}:
+		if i, err := strconv.Atoi(last); err == nil && i >= 0 && i < len(n) {
+			n[i] = Redacted
+			return true
+		}
+	}
+	return false
+}
+
+func normaliseFieldName(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "", " ", "").Replace(name))
+}
@@ -0,0 +1,159 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/go-logger/v2"
+)
+
+// File is a secret read from a mounted file, such as a Kubernetes secret volume.
+// The file is re-read every refresh interval, so that a rotated secret is used without restarting the service.
+// An unreadable, empty or invalid file keeps the previous value, as it is usually being replaced.
+type File struct {
+	path     string
+	validate func(string) error
+	log      *logger.UPPLogger
+
+	mu        sync.RWMutex
+	value     string
+	listeners []func(string)
+	// notifyMu serialises the calls of the listeners, made without holding mu so that they can read the secret
+	notifyMu sync.Mutex
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewFile reads the secret of the file, and re-reads it every refresh interval unless it is zero.
+// The secret must pass validate, which may be nil, such as ValidateBasicAuth.
+func NewFile(path string, refresh time.Duration, validate func(string) error, log *logger.UPPLogger) (*File, error) {
+	value, err := readSecret(path, validate)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{
+		path:     path,
+		validate: validate,
+		log:      log,
+		value:    value,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	if refresh > 0 {
+		go f.watch(refresh)
+	} else {
+		close(f.done)
+	}
+	return f, nil
+}
+
+// Value returns the current secret.
+func (f *File) Value() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.value
+}
+
+// OnChange calls the listener with the current secret, then with every rotated one.
+// Listeners are called in turn, and must not block nor reload the file; they may read its Value.
+func (f *File) OnChange(listener func(string)) {
+	f.notifyMu.Lock()
+	defer f.notifyMu.Unlock()
+
+	f.mu.Lock()
+	f.listeners = append(f.listeners, listener)
+	value := f.value
+	f.mu.Unlock()
+
+	listener(value)
+}
+
+// Reload re-reads the file and reports whether the secret has changed.
+func (f *File) Reload() (bool, error) {
+	f.notifyMu.Lock()
+	defer f.notifyMu.Unlock()
+
+	value, err := readSecret(f.path, f.validate)
+	if err != nil {
+		return false, err
+	}
+
+	f.mu.Lock()
+	if value == f.value {
+		f.mu.Unlock()
+		return false, nil
+	}
+	f.value = value
+	listeners := append([]func(string){}, f.listeners...)
+	f.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(value)
+	}
+	return true, nil
+}
+
+// Close stops re-reading the file.
+func (f *File) Close() {
+	select {
+	case <-f.stop:
+	default:
+		close(f.stop)
+	}
+	<-f.done
+}
+
+func (f *File) watch(refresh time.Duration) {
+	defer close(f.done)
+
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-ticker.C:
+			changed, err := f.Reload()
+			if err != nil {
+				f.log.WithError(err).WithField("path", f.path).Error("Unable to reload secret, keeping its previous value")
+			} else if changed {
+				f.log.WithField("path", f.path).Info("Secret rotated")
+			}
+		}
+	}
+}
+
+// ValidateBasicAuth checks that a secret is basic auth credentials, of the form username:password.
+func ValidateBasicAuth(value string) error {
+	if !strings.Contains(value, ":") {
+		return errors.New("basic auth is not of the form username:password")
+	}
+	return nil
+}
+
+func readSecret(path string, validate func(string) error) (string, error) {
+	by, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	value := strings.TrimSpace(string(by))
+	if value == "" {
+		return "", fmt.Errorf("secret file %s is empty", path)
+	}
+	if validate != nil {
+		if err = validate(value); err != nil {
+			return "", fmt.Errorf("secret file %s: %w", path, err)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,131 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSecret(t *testing.T, path string, value string) {
+	require.NoError(t, os.WriteFile(path, []byte(value), 0600))
+}
+
+func TestNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "delivery-basic-auth")
+	writeSecret(t, path, "user:pass\n")
+
+	secret, err := NewFile(path, 0, nil, logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	defer secret.Close()
+
+	assert.Equal(t, "user:pass", secret.Value(), "surrounding whitespace is trimmed")
+}
+
+func TestNewFileMissingOrEmpty(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewFile(filepath.Join(dir, "missing"), 0, nil, logger.NewUPPLogger("test logger", "debug"))
+	assert.Error(t, err)
+
+	path := filepath.Join(dir, "empty")
+	writeSecret(t, path, "\n")
+	_, err = NewFile(path, 0, nil, logger.NewUPPLogger("test logger", "debug"))
+	assert.EqualError(t, err, "secret file "+path+" is empty")
+}
+
+func TestFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeSecret(t, path, "first-token")
+
+	secret, err := NewFile(path, 0, nil, logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	defer secret.Close()
+
+	var seen []string
+	secret.OnChange(func(value string) { seen = append(seen, value) })
+
+	changed, err := secret.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	writeSecret(t, path, "second-token")
+	changed, err = secret.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "second-token", secret.Value())
+
+	writeSecret(t, path, "")
+	_, err = secret.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, "second-token", secret.Value(), "an empty file keeps the previous secret")
+
+	assert.Equal(t, []string{"first-token", "second-token"}, seen)
+}
+
+func TestFileReloadValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "delivery-basic-auth")
+	writeSecret(t, path, "user")
+	_, err := NewFile(path, 0, ValidateBasicAuth, logger.NewUPPLogger("test logger", "debug"))
+	assert.Error(t, err)
+
+	writeSecret(t, path, "user:pass")
+	secret, err := NewFile(path, 0, ValidateBasicAuth, logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	defer secret.Close()
+
+	var seen []string
+	secret.OnChange(func(value string) { seen = append(seen, value) })
+
+	writeSecret(t, path, "rotated-user")
+	_, err = secret.Reload()
+	assert.EqualError(t, err, "secret file "+path+": basic auth is not of the form username:password")
+	assert.Equal(t, "user:pass", secret.Value(), "an invalid secret keeps the previous one")
+	assert.Equal(t, []string{"user:pass"}, seen)
+}
+
+func TestFileListenersReadValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeSecret(t, path, "first-token")
+
+	secret, err := NewFile(path, 0, nil, logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	defer secret.Close()
+
+	var seen []string
+	secret.OnChange(func(string) { seen = append(seen, secret.Value()) })
+
+	writeSecret(t, path, "second-token")
+	_, err = secret.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first-token", "second-token"}, seen)
+}
+
+func TestFileWatchesRotations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeSecret(t, path, "first-token")
+
+	secret, err := NewFile(path, 10*time.Millisecond, nil, logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	defer secret.Close()
+
+	var mu sync.Mutex
+	rotated := ""
+	secret.OnChange(func(value string) {
+		mu.Lock()
+		defer mu.Unlock()
+		rotated = value
+	})
+
+	writeSecret(t, path, "second-token")
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return rotated == "second-token"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "second-token", secret.Value())
+}
@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup(t *testing.T) {
+	for _, exporter := range []string{"none", "stdout"} {
+		t.Run(exporter, func(t *testing.T) {
+			shutdown, err := Setup(context.TODO(), exporter, "", "draft-content-api", 1)
+
+			assert.NoError(t, err)
+			assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+			assert.NoError(t, shutdown(context.TODO()))
+		})
+	}
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	_, err := Setup(context.TODO(), "zipkin", "", "draft-content-api", 1)
+
+	assert.Error(t, err)
+}
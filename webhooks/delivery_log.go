@@ -0,0 +1,70 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	DeliverySucceeded = "succeeded"
+	DeliveryRetrying  = "retrying"
+	DeliveryFailed    = "failed"
+	DeliveryDropped   = "dropped"
+)
+
+// Delivery records a single attempt to deliver a payload to a webhook.
+type Delivery struct {
+	ID            string    `json:"id"`
+	Webhook       string    `json:"webhook"`
+	Event         string    `json:"event"`
+	UUID          string    `json:"uuid"`
+	TransactionID string    `json:"transactionId"`
+	Attempt       int       `json:"attempt"`
+	StatusCode    int       `json:"statusCode,omitempty"`
+	Status        string    `json:"status"`
+	Error         string    `json:"error,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// DeliveryLog keeps the most recent webhook deliveries in memory, for support.
+type DeliveryLog struct {
+	mu         sync.Mutex
+	size       int
+	deliveries []Delivery
+}
+
+func NewDeliveryLog(size int) *DeliveryLog {
+	return &DeliveryLog{size: size}
+}
+
+func (l *DeliveryLog) add(delivery Delivery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.deliveries = append(l.deliveries, delivery)
+	if len(l.deliveries) > l.size {
+		l.deliveries = l.deliveries[len(l.deliveries)-l.size:]
+	}
+}
+
+// Deliveries returns the recorded deliveries of the given webhook, or of all webhooks if it is empty, newest first.
+func (l *DeliveryLog) Deliveries(webhook string) []Delivery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := []Delivery{}
+	for i := len(l.deliveries) - 1; i >= 0; i-- {
+		if webhook == "" || l.deliveries[i].Webhook == webhook {
+			result = append(result, l.deliveries[i])
+		}
+	}
+	return result
+}
+
+// ServeHTTP lists the recorded deliveries, filtered on the webhook query parameter.
+func (l *DeliveryLog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(l.Deliveries(r.URL.Query().Get("webhook")))
+}
@@ -0,0 +1,354 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/config"
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/draft-content-api/events"
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/google/uuid"
+)
+
+const (
+	EventDraftChanged          = "draft-changed"
+	EventDraftValidationFailed = "draft-validation-failed"
+
+	signatureHeader = "X-Webhook-Signature"
+	eventHeader     = "X-Webhook-Event"
+	deliveryHeader  = "X-Webhook-Delivery"
+)
+
+// deliveryNamespace derives the delivery ids, so that every attempt to deliver an event to a webhook,
+// including after a restart, carries the same id and receivers can deduplicate them.
+var deliveryNamespace = uuid.MustParse("5d1d3b0e-8f0c-4a4e-9a3c-2f6e0c7b9d41")
+
+// Payload is the body posted to webhooks.
+type Payload struct {
+	ID    string             `json:"id"`
+	Event string             `json:"event"`
+	Draft content.DraftEvent `json:"draft"`
+}
+
+type webhook struct {
+	config.WebhookConfig
+	events       map[string]struct{}
+	contentTypes map[string]struct{}
+	outboxes     map[string]*events.Outbox
+}
+
+func (wh *webhook) matches(eventType string, contentType string) bool {
+	if _, found := wh.events[eventType]; len(wh.events) > 0 && !found {
+		return false
+	}
+	if _, found := wh.contentTypes[mediaType(contentType)]; len(wh.contentTypes) > 0 && !found {
+		return false
+	}
+	return true
+}
+
+// Dispatcher posts signed draft events to the configured webhooks. Every webhook and event type has its own outbox,
+// so that a slow or failing partner does not delay the others, and failed deliveries are rescheduled
+// with an exponential backoff without holding up the events behind them.
+// It implements content.DraftObserver and content.DraftValidationObserver.
+type Dispatcher struct {
+	webhooks   []*webhook
+	deliveries *DeliveryLog
+	log        *logger.UPPLogger
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewDispatcher validates the webhooks and starts delivering to them. Up to queueSize payloads are scheduled
+// per webhook and event type, the others wait in its outbox store: a database file in storeDir,
+// or memory if storeDir is empty. Each payload is attempted at most maxRetries+1 times,
+// waiting backoff, then twice as long, between attempts.
+func NewDispatcher(configs []config.WebhookConfig, httpClient *http.Client, queueSize int, maxRetries int, backoff time.Duration, storeDir string, deliveries *DeliveryLog, log *logger.UPPLogger) (*Dispatcher, error) {
+	d := &Dispatcher{
+		deliveries: deliveries,
+		log:        log,
+	}
+
+	for _, cfg := range configs {
+		wh, err := newWebhook(cfg)
+		if err != nil {
+			return nil, err
+		}
+		d.webhooks = append(d.webhooks, wh)
+	}
+
+	for _, wh := range d.webhooks {
+		for _, eventType := range []string{EventDraftChanged, EventDraftValidationFailed} {
+			if _, found := wh.events[eventType]; len(wh.events) > 0 && !found {
+				continue
+			}
+
+			store, err := newOutboxStore(storeDir, wh.Name, eventType)
+			if err != nil {
+				// the outboxes opened already are closed without publishing anything
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				d.closeOutboxes(ctx)
+				return nil, fmt.Errorf("unable to open the outbox of webhook %s: %w", wh.Name, err)
+			}
+			publisher := &webhookPublisher{
+				webhook:    wh,
+				event:      eventType,
+				httpClient: httpClient,
+				maxRetries: maxRetries,
+				deliveries: deliveries,
+				log:        log,
+				attempts:   map[string]int{},
+			}
+			wh.outboxes[eventType] = events.NewOutbox(publisher, store, queueSize, maxRetries, backoff, log)
+		}
+	}
+	return d, nil
+}
+
+func newWebhook(cfg config.WebhookConfig) (*webhook, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("webhook without a name")
+	}
+	if u, err := url.Parse(cfg.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("webhook %s has an invalid url: %q", cfg.Name, cfg.URL)
+	}
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("webhook %s has no secret", cfg.Name)
+	}
+
+	wh := &webhook{
+		WebhookConfig: cfg,
+		events:        map[string]struct{}{},
+		contentTypes:  map[string]struct{}{},
+		outboxes:      map[string]*events.Outbox{},
+	}
+	for _, event := range cfg.Events {
+		if event != EventDraftChanged && event != EventDraftValidationFailed {
+			return nil, fmt.Errorf("webhook %s has an unknown event: %q", cfg.Name, event)
+		}
+		wh.events[event] = struct{}{}
+	}
+	for _, contentType := range cfg.ContentTypes {
+		wh.contentTypes[mediaType(contentType)] = struct{}{}
+	}
+	return wh, nil
+}
+
+// mediaType drops the parameters of a content type, such as its charset, so that webhooks filter on the media type only.
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+	return contentType
+}
+
+func newOutboxStore(dir string, webhook string, eventType string) (events.OutboxStore, error) {
+	if dir == "" {
+		return events.NewMemoryOutboxStore(), nil
+	}
+	return events.NewBoltOutboxStore(filepath.Join(dir, url.PathEscape(webhook)+"-"+eventType+".db"))
+}
+
+func (d *Dispatcher) DraftChanged(ctx context.Context, event content.DraftEvent) {
+	d.dispatch(ctx, EventDraftChanged, event)
+}
+
+func (d *Dispatcher) DraftValidationFailed(ctx context.Context, event content.DraftEvent) {
+	d.dispatch(ctx, EventDraftValidationFailed, event)
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, eventType string, event content.DraftEvent) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, wh := range d.webhooks {
+		if !wh.matches(eventType, event.ContentType) {
+			continue
+		}
+		if d.closed {
+			d.drop(wh, eventType, event, "webhook dispatcher is closed")
+			continue
+		}
+		wh.outboxes[eventType].DraftChanged(ctx, event)
+	}
+}
+
+func (d *Dispatcher) drop(wh *webhook, eventType string, event content.DraftEvent, reason string) {
+	d.log.WithField(tidutils.TransactionIDHeader, event.TransactionID).
+		WithField("uuid", event.UUID).
+		WithField("webhook", wh.Name).
+		Error("Dropping webhook delivery: " + reason)
+	d.deliveries.add(Delivery{
+		ID:            deliveryID(wh.Name, eventType, event),
+		Webhook:       wh.Name,
+		Event:         eventType,
+		UUID:          event.UUID,
+		TransactionID: event.TransactionID,
+		Status:        DeliveryDropped,
+		Error:         reason,
+		Timestamp:     time.Now().UTC(),
+	})
+}
+
+// Close stops accepting events and delivers those that are due until ctx is done.
+// The payloads left are kept in the outbox stores.
+func (d *Dispatcher) Close(ctx context.Context) {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	d.closeOutboxes(ctx)
+}
+
+func (d *Dispatcher) closeOutboxes(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, wh := range d.webhooks {
+		for _, outbox := range wh.outboxes {
+			wg.Add(1)
+			go func(outbox *events.Outbox) {
+				defer wg.Done()
+				outbox.Close(ctx)
+			}(outbox)
+		}
+	}
+	wg.Wait()
+}
+
+func deliveryID(webhook string, eventType string, event content.DraftEvent) string {
+	name := strings.Join([]string{webhook, eventType, event.UUID, event.WriteReference, event.Timestamp.Format(time.RFC3339Nano)}, "/")
+	return uuid.NewSHA1(deliveryNamespace, []byte(name)).String()
+}
+
+// webhookPublisher posts the events of an outbox to its webhook, and records the deliveries.
+// It gives up on a payload, reporting it as published, after maxRetries+1 attempts or a response that is not worth retrying.
+type webhookPublisher struct {
+	webhook    *webhook
+	event      string
+	httpClient *http.Client
+	maxRetries int
+	deliveries *DeliveryLog
+	log        *logger.UPPLogger
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func (p *webhookPublisher) Publish(ctx context.Context, event content.DraftEvent) error {
+	deliveryLog := p.log.WithField(tidutils.TransactionIDHeader, event.TransactionID).
+		WithField("uuid", event.UUID).
+		WithField("webhook", p.webhook.Name)
+
+	payload := Payload{ID: deliveryID(p.webhook.Name, p.event, event), Event: p.event, Draft: event}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		deliveryLog.WithError(err).Error("Unable to marshal webhook payload")
+		return nil
+	}
+
+	attempt := p.attempt(payload.ID)
+	statusCode, err := p.post(ctx, payload, body)
+	retryable := err != nil || statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+	if err == nil && (statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices) {
+		err = fmt.Errorf("webhook responded with status %d", statusCode)
+	}
+
+	delivery := Delivery{
+		ID:            payload.ID,
+		Webhook:       p.webhook.Name,
+		Event:         payload.Event,
+		UUID:          event.UUID,
+		TransactionID: event.TransactionID,
+		Attempt:       attempt,
+		StatusCode:    statusCode,
+		Status:        DeliverySucceeded,
+		Timestamp:     time.Now().UTC(),
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+		delivery.Status = DeliveryFailed
+		if retryable && attempt <= p.maxRetries {
+			delivery.Status = DeliveryRetrying
+		}
+	}
+	p.deliveries.add(delivery)
+
+	switch delivery.Status {
+	case DeliverySucceeded:
+		p.forget(payload.ID)
+		return nil
+	case DeliveryFailed:
+		p.forget(payload.ID)
+		deliveryLog.WithError(err).Error("Unable to deliver webhook, giving up")
+		return nil
+	}
+	return fmt.Errorf("webhook %s: %w", p.webhook.Name, err)
+}
+
+func (p *webhookPublisher) attempt(id string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.attempts[id]++
+	return p.attempts[id]
+}
+
+func (p *webhookPublisher) forget(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.attempts, id)
+}
+
+func (p *webhookPublisher) post(ctx context.Context, payload Payload, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(tidutils.TransactionIDHeader, payload.Draft.TransactionID)
+	req.Header.Set(eventHeader, payload.Event)
+	req.Header.Set(deliveryHeader, payload.ID)
+	req.Header.Set(signatureHeader, Sign(p.webhook.Secret, time.Now(), body))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		// the url error repeats the webhook url, which may carry a token, in the delivery log
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// Sign returns the X-Webhook-Signature header of a payload: the signing time, and the HMAC-SHA256
+// of the signing time and the body, so that receivers can check the payload was sent recently by this service.
+func Sign(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return "t=" + timestamp + ",sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
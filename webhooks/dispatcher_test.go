@@ -0,0 +1,287 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/draft-content-api/config"
+	"github.com/Financial-Times/draft-content-api/content"
+	"github.com/Financial-Times/go-logger/v2"
+	tidutils "github.com/Financial-Times/transactionid-utils-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret      = "s3cret"
+	articleType     = "application/vnd.ft-upp-article+json"
+	placeholderType = "application/vnd.ft-upp-content-placeholder+json"
+)
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+// partner records the webhooks it receives, responding with the given statuses in turn and 200 after them.
+type partner struct {
+	mu       sync.Mutex
+	statuses []int
+	received []receivedWebhook
+}
+
+func (p *partner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.received = append(p.received, receivedWebhook{r.Header, body})
+	status := http.StatusOK
+	if len(p.statuses) > 0 {
+		status, p.statuses = p.statuses[0], p.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (p *partner) webhooks() []receivedWebhook {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]receivedWebhook(nil), p.received...)
+}
+
+func newTestDispatcher(t *testing.T, configs []config.WebhookConfig, storeDir string, deliveries *DeliveryLog) *Dispatcher {
+	d, err := NewDispatcher(configs, http.DefaultClient, 10, 2, time.Millisecond, storeDir, deliveries, logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	return d
+}
+
+func closeDispatcher(d *Dispatcher) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	d.Close(ctx)
+}
+
+func testEvent(contentType string) content.DraftEvent {
+	return content.DraftEvent{
+		UUID:           "83a201c6-60cd-11e7-91a7-502f7ee26895",
+		ContentType:    contentType,
+		OriginSystemID: "cct",
+		TransactionID:  "test_tid",
+		WriteReference: "test_tid",
+		Timestamp:      time.Date(2018, 2, 21, 14, 25, 0, 0, time.UTC),
+	}
+}
+
+func TestDispatcherPostsSignedPayloads(t *testing.T) {
+	p := &partner{}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	d := newTestDispatcher(t, []config.WebhookConfig{{Name: "partner", URL: server.URL, Secret: testSecret}}, "", NewDeliveryLog(10))
+	d.DraftChanged(context.TODO(), testEvent(articleType))
+	closeDispatcher(d)
+
+	received := p.webhooks()
+	require.Len(t, received, 1)
+	assert.Equal(t, EventDraftChanged, received[0].header.Get(eventHeader))
+	assert.Equal(t, "test_tid", received[0].header.Get(tidutils.TransactionIDHeader))
+
+	signature := received[0].header.Get(signatureHeader)
+	timestamp, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, signature, Sign(testSecret, time.Unix(timestamp, 0), received[0].body))
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(received[0].body, &payload))
+	assert.Equal(t, received[0].header.Get(deliveryHeader), payload.ID)
+	assert.Equal(t, testEvent(articleType), payload.Draft)
+}
+
+func TestDispatcherFiltersWebhooks(t *testing.T) {
+	p := &partner{}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	d := newTestDispatcher(t, []config.WebhookConfig{{
+		Name:         "partner",
+		URL:          server.URL,
+		Secret:       testSecret,
+		Events:       []string{EventDraftValidationFailed},
+		ContentTypes: []string{articleType},
+	}}, "", NewDeliveryLog(10))
+	d.DraftChanged(context.TODO(), testEvent(articleType))
+	d.DraftValidationFailed(context.TODO(), testEvent(placeholderType))
+	d.DraftValidationFailed(context.TODO(), testEvent(articleType))
+	closeDispatcher(d)
+
+	received := p.webhooks()
+	require.Len(t, received, 1)
+	assert.Equal(t, EventDraftValidationFailed, received[0].header.Get(eventHeader))
+}
+
+func TestDispatcherFiltersOnTheMediaType(t *testing.T) {
+	p := &partner{}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	d := newTestDispatcher(t, []config.WebhookConfig{{
+		Name:         "partner",
+		URL:          server.URL,
+		Secret:       testSecret,
+		ContentTypes: []string{articleType + "; charset=utf-8"},
+	}}, "", NewDeliveryLog(10))
+	d.DraftChanged(context.TODO(), testEvent(articleType+"; charset=utf-8"))
+	d.DraftChanged(context.TODO(), testEvent(articleType))
+	d.DraftChanged(context.TODO(), testEvent(placeholderType+"; charset=utf-8"))
+	closeDispatcher(d)
+
+	assert.Len(t, p.webhooks(), 2, "content types match whatever their parameters")
+}
+
+func TestDispatcherRetriesFailedDeliveries(t *testing.T) {
+	p := &partner{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	deliveries := NewDeliveryLog(10)
+	d := newTestDispatcher(t, []config.WebhookConfig{{Name: "partner", URL: server.URL, Secret: testSecret}}, "", deliveries)
+	d.DraftChanged(context.TODO(), testEvent(articleType))
+	assert.Eventually(t, func() bool { return len(p.webhooks()) == 3 }, time.Second, time.Millisecond)
+	closeDispatcher(d)
+
+	received := p.webhooks()
+	assert.Equal(t, received[0].header.Get(deliveryHeader), received[2].header.Get(deliveryHeader), "retries carry the same delivery id")
+	logged := deliveries.Deliveries("partner")
+	require.Len(t, logged, 3)
+	assert.Equal(t, DeliverySucceeded, logged[0].Status)
+	assert.Equal(t, 3, logged[0].Attempt)
+	assert.Equal(t, DeliveryRetrying, logged[1].Status)
+	assert.Equal(t, http.StatusTooManyRequests, logged[1].StatusCode)
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	p := &partner{statuses: []int{http.StatusBadRequest}}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	deliveries := NewDeliveryLog(10)
+	d := newTestDispatcher(t, []config.WebhookConfig{{Name: "partner", URL: server.URL, Secret: testSecret}}, "", deliveries)
+	d.DraftChanged(context.TODO(), testEvent(articleType))
+	closeDispatcher(d)
+
+	assert.Len(t, p.webhooks(), 1, "client errors are not retried")
+	logged := deliveries.Deliveries("")
+	require.Len(t, logged, 1)
+	assert.Equal(t, DeliveryFailed, logged[0].Status)
+}
+
+func TestDispatcherRetriesDoNotHoldUpOtherDeliveries(t *testing.T) {
+	p := &partner{statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	d, err := NewDispatcher([]config.WebhookConfig{{Name: "partner", URL: server.URL, Secret: testSecret}}, http.DefaultClient, 10, 2, time.Hour, "", NewDeliveryLog(10), logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	d.DraftChanged(context.TODO(), testEvent(articleType))
+	assert.Eventually(t, func() bool { return len(p.webhooks()) == 1 }, time.Second, time.Millisecond)
+
+	other := testEvent(articleType)
+	other.UUID = "fba9884e-0756-11e8-0074-38e932af9738"
+	other.WriteReference = "other_tid"
+	d.DraftChanged(context.TODO(), other)
+	assert.Eventually(t, func() bool { return len(p.webhooks()) == 2 }, time.Second, time.Millisecond,
+		"the payload of another draft is delivered while the failed one waits for its retry")
+	closeDispatcher(d)
+}
+
+func TestDispatcherDeliversPayloadsLeftByAPreviousRun(t *testing.T) {
+	dir := t.TempDir()
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+
+	d, err := NewDispatcher([]config.WebhookConfig{{Name: "partner", URL: unavailable.URL, Secret: testSecret}}, http.DefaultClient, 10, 2, time.Hour, dir, NewDeliveryLog(10), logger.NewUPPLogger("test logger", "debug"))
+	require.NoError(t, err)
+	d.DraftChanged(context.TODO(), testEvent(articleType))
+	closeDispatcher(d)
+
+	p := &partner{}
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	d = newTestDispatcher(t, []config.WebhookConfig{{Name: "partner", URL: server.URL, Secret: testSecret}}, dir, NewDeliveryLog(10))
+	assert.Eventually(t, func() bool { return len(p.webhooks()) == 1 }, time.Second, time.Millisecond)
+	closeDispatcher(d)
+}
+
+func TestDispatcherCloseHasADeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	d := newTestDispatcher(t, []config.WebhookConfig{{Name: "partner", URL: server.URL, Secret: testSecret}}, "", NewDeliveryLog(10))
+	d.DraftChanged(context.TODO(), testEvent(articleType))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	d.Close(ctx)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestDispatcherDropsAfterClose(t *testing.T) {
+	deliveries := NewDeliveryLog(10)
+	d := newTestDispatcher(t, []config.WebhookConfig{{Name: "partner", URL: "http://partner", Secret: testSecret}}, "", deliveries)
+	closeDispatcher(d)
+
+	d.DraftChanged(context.TODO(), testEvent(articleType))
+	logged := deliveries.Deliveries("partner")
+	require.Len(t, logged, 1)
+	assert.Equal(t, DeliveryDropped, logged[0].Status)
+}
+
+func TestNewDispatcherInvalidConfig(t *testing.T) {
+	tests := map[string]config.WebhookConfig{
+		"no name":       {URL: "http://partner", Secret: testSecret},
+		"invalid url":   {Name: "partner", URL: "partner", Secret: testSecret},
+		"no secret":     {Name: "partner", URL: "http://partner"},
+		"unknown event": {Name: "partner", URL: "http://partner", Secret: testSecret, Events: []string{"draft-deleted"}},
+	}
+
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewDispatcher([]config.WebhookConfig{cfg}, http.DefaultClient, 10, 2, time.Millisecond, "", NewDeliveryLog(10), logger.NewUPPLogger("test logger", "debug"))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDeliveryLogEndpoint(t *testing.T) {
+	deliveries := NewDeliveryLog(2)
+	deliveries.add(Delivery{ID: "1", Webhook: "a"})
+	deliveries.add(Delivery{ID: "2", Webhook: "b"})
+	deliveries.add(Delivery{ID: "3", Webhook: "a"})
+
+	w := httptest.NewRecorder()
+	deliveries.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/__webhooks/deliveries?webhook=a", nil))
+
+	var logged []Delivery
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &logged))
+	require.Len(t, logged, 1, "the oldest delivery is evicted")
+	assert.Equal(t, "3", logged[0].ID)
+}